@@ -0,0 +1,255 @@
+package pkggodevclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+type AuditRequest struct {
+	// GoMod is the contents of the go.mod file to audit
+	GoMod []byte
+	// GoSum is the contents of the module's go.sum file, which is optional.
+	// If it is set, requirements without a checksum in it are flagged.
+	GoSum []byte
+	// SkipIndirect skips the requirements that are marked "// indirect"
+	SkipIndirect bool
+	// Concurrency is the maximum number of modules to look up at once, which defaults to 4.
+	Concurrency int
+}
+
+type FindingKind string
+
+const (
+	FindingNonRedistributableLicense FindingKind = "non-redistributable-license"
+	FindingNoTaggedVersion           FindingKind = "no-tagged-version"
+	FindingNoStableVersion           FindingKind = "no-stable-version"
+	FindingInvalidGoMod              FindingKind = "invalid-go-mod"
+	FindingDeprecated                FindingKind = "deprecated"
+	// FindingOutdated is a requirement that is behind the latest version of its major version
+	FindingOutdated FindingKind = "outdated"
+	// FindingNewMajorVersion is a requirement of a module that has a higher major version
+	FindingNewMajorVersion FindingKind = "new-major-version"
+	// FindingMissingChecksum is a requirement without a checksum in the go.sum file
+	FindingMissingChecksum FindingKind = "missing-checksum"
+	// FindingReplacedByDirectory is a requirement that a replace directive replaces with a local directory,
+	// which pkg.go.dev doesn't know about, so it isn't audited
+	FindingReplacedByDirectory FindingKind = "replaced-by-directory"
+)
+
+// Finding is a problem with a required module.
+type Finding struct {
+	Kind    FindingKind
+	Message string
+}
+
+type AuditReport struct {
+	// Module is the module path of the go.mod file
+	Module string
+	// Modules are the audits of the required modules, in the order of the go.mod file
+	Modules []ModuleAudit
+}
+
+// ModuleAudit is the audit of a required module.
+type ModuleAudit struct {
+	Path     string
+	Version  string
+	Indirect bool
+	// ReplacePath and ReplaceVersion are the replacement of the requirement by a replace directive, if any,
+	// which is audited instead of the required module. ReplaceVersion is empty if ReplacePath is a local directory.
+	ReplacePath    string `json:",omitempty"`
+	ReplaceVersion string `json:",omitempty"`
+	License        string
+	// LatestVersion is the highest version of the audited module with the same major version as its version
+	LatestVersion string
+	Findings      []Finding
+	// Err is the error that prevented the module from being audited, such as ErrNotFound for private modules.
+	// It is marshaled to JSON as its message in an Error field.
+	Err error `json:"-"`
+}
+
+func (m ModuleAudit) MarshalJSON() ([]byte, error) {
+	// moduleAudit doesn't have the MarshalJSON method, which would recurse
+	type moduleAudit ModuleAudit
+	v := struct {
+		moduleAudit
+		Error string `json:",omitempty"`
+	}{moduleAudit: moduleAudit(m)}
+	if m.Err != nil {
+		v.Error = m.Err.Error()
+	}
+	return json.Marshal(v)
+}
+
+func (c *client) Audit(req AuditRequest) (*AuditReport, error) {
+	return c.AuditContext(context.Background(), req)
+}
+
+// AuditContext describes each module that the go.mod file requires at its required version, and gets its versions,
+// to find problems with the requirements. A requirement that a replace directive replaces with another module
+// is audited as that module, and one that it replaces with a local directory is only reported. Exclude directives are ignored.
+// A module that can't be looked up doesn't fail the audit, and its error is in its ModuleAudit instead.
+func (c *client) AuditContext(ctx context.Context, req AuditRequest) (*AuditReport, error) {
+	// unlike Parse, ParseLax ignores replace directives, since they only apply in the main module
+	goMod, err := modfile.Parse("go.mod", req.GoMod, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parsing go.mod: %w", err)
+	}
+	if goMod.Module == nil {
+		return nil, errors.New("parsing go.mod: no module directive")
+	}
+	var checksums map[string]bool
+	if req.GoSum != nil {
+		checksums = parseGoSum(req.GoSum)
+	}
+
+	report := &AuditReport{Module: goMod.Module.Mod.Path}
+	for _, r := range goMod.Require {
+		if r.Indirect && req.SkipIndirect {
+			continue
+		}
+		m := ModuleAudit{Path: r.Mod.Path, Version: r.Mod.Version, Indirect: r.Indirect}
+		if rep := findReplace(goMod.Replace, r.Mod.Path, r.Mod.Version); rep != nil {
+			m.ReplacePath, m.ReplaceVersion = rep.New.Path, rep.New.Version
+		}
+		report.Modules = append(report.Modules, m)
+	}
+
+	runBatch(ctx, len(report.Modules), req.Concurrency, func(i int) {
+		m := &report.Modules[i]
+		if m.ReplacePath != "" && m.ReplaceVersion == "" {
+			m.Findings = append(m.Findings, Finding{Kind: FindingReplacedByDirectory, Message: fmt.Sprintf("replaced by the local directory '%s', which isn't audited", m.ReplacePath)})
+			return
+		}
+		path, version := m.audited()
+		if checksums != nil && !checksums[path+" "+version] && !checksums[path+" "+version+"/go.mod"] {
+			m.Findings = append(m.Findings, Finding{Kind: FindingMissingChecksum, Message: "no checksum in go.sum"})
+		}
+		p, err := c.DescribePackageContext(ctx, DescribePackageRequest{Package: path + "@" + version, AllowModule: true})
+		if err != nil {
+			m.Err = fmt.Errorf("describing module: %w", err)
+			return
+		}
+		versions, err := c.VersionsContext(ctx, VersionsRequest{Package: path})
+		if err != nil {
+			m.Err = fmt.Errorf("getting versions: %w", err)
+			return
+		}
+		auditModule(m, p, versions)
+	})
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// findReplace returns the replace directive that applies to the given version of a module, if any.
+// Like the go command, a replacement of the specific version takes precedence over one of all versions.
+func findReplace(replaces []*modfile.Replace, path, version string) *modfile.Replace {
+	var all *modfile.Replace
+	for _, r := range replaces {
+		if r.Old.Path != path {
+			continue
+		}
+		if r.Old.Version == version {
+			return r
+		}
+		if r.Old.Version == "" {
+			all = r
+		}
+	}
+	return all
+}
+
+// audited returns the path and version of the module that is audited for the requirement,
+// which is its replacement if it has one.
+func (m *ModuleAudit) audited() (path, version string) {
+	if m.ReplacePath != "" {
+		return m.ReplacePath, m.ReplaceVersion
+	}
+	return m.Path, m.Version
+}
+
+// auditModule adds the findings about a required module from its package information and versions.
+func auditModule(m *ModuleAudit, p *Package, versions *Versions) {
+	m.License = p.License
+	if !p.HasRedistributableLicense {
+		msg := "license is not redistributable"
+		if p.License != "" {
+			msg = fmt.Sprintf("license '%s' is not redistributable", p.License)
+		}
+		m.Findings = append(m.Findings, Finding{Kind: FindingNonRedistributableLicense, Message: msg})
+	}
+	if !p.HasTaggedVersion {
+		m.Findings = append(m.Findings, Finding{Kind: FindingNoTaggedVersion, Message: "module has no tagged version"})
+	}
+	if !p.HasStableVersion {
+		m.Findings = append(m.Findings, Finding{Kind: FindingNoStableVersion, Message: "module has no stable version"})
+	}
+	if !p.HasValidGoModFile {
+		m.Findings = append(m.Findings, Finding{Kind: FindingInvalidGoMod, Message: "module has no valid go.mod file"})
+	}
+	if p.Deprecated {
+		msg := "module is deprecated"
+		if p.DeprecationMessage != "" {
+			msg += ": " + p.DeprecationMessage
+		}
+		m.Findings = append(m.Findings, Finding{Kind: FindingDeprecated, Message: msg})
+	}
+
+	path, version := m.audited()
+	required := parseSemVer(version)
+	if required == nil {
+		return
+	}
+	if latest := latestVersion(versions.Versions, required.Major); latest != nil {
+		m.LatestVersion = latest.String()
+		if required.Less(*latest) {
+			m.Findings = append(m.Findings, Finding{Kind: FindingOutdated, Message: fmt.Sprintf("%s is behind the latest version %s", version, m.LatestVersion)})
+		}
+	}
+	if p.LatestMajorVersion != "" && p.LatestMajorVersion != path {
+		m.Findings = append(m.Findings, Finding{Kind: FindingNewMajorVersion, Message: fmt.Sprintf("a newer major version is available at %s", p.LatestMajorVersion)})
+	}
+}
+
+// latestVersion returns the highest version with the given major version, ignoring pseudo-versions,
+// or nil if there is none. Like the go command, it prefers releases to prereleases.
+func latestVersion(versions []Version, major int) *SemVer {
+	var latest, latestPrerelease *SemVer
+	for _, v := range versions {
+		if v.SemVer == nil || v.SemVer.IsPseudo || v.SemVer.Major != major {
+			continue
+		}
+		if v.SemVer.IsPrerelease() {
+			if latestPrerelease == nil || latestPrerelease.Less(*v.SemVer) {
+				latestPrerelease = v.SemVer
+			}
+			continue
+		}
+		if latest == nil || latest.Less(*v.SemVer) {
+			latest = v.SemVer
+		}
+	}
+	if latest != nil {
+		return latest
+	}
+	return latestPrerelease
+}
+
+// parseGoSum returns the set of "path version" and "path version/go.mod" entries in a go.sum file.
+func parseGoSum(goSum []byte) map[string]bool {
+	checksums := map[string]bool{}
+	for _, line := range strings.Split(string(goSum), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		checksums[fields[0]+" "+fields[1]] = true
+	}
+	return checksums
+}
@@ -0,0 +1,237 @@
+package pkggodevclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// auditPackagePage returns the page of a module root that is also a package, with the given checks, license and banners.
+func auditPackagePage(license string, passed map[string]bool, banners string) string {
+	return auditUnitPage("<div>module</div><div>package</div>", license, passed, banners)
+}
+
+// auditUnitPage returns a page with the given unit kinds after its heading, checks, license and banners.
+func auditUnitPage(kinds string, license string, passed map[string]bool, banners string) string {
+	checks := ""
+	for _, label := range []string{"Valid go.mod file", "Redistributable license", "Tagged version", "Stable version"} {
+		alt := "unchecked"
+		if passed[label] {
+			alt = "checked"
+		}
+		checks += fmt.Sprintf(`<li><img alt="%s"/>%s</li>`, alt, label)
+	}
+	return fmt.Sprintf(`<html>%s
+<div><div class="UnitHeader-titleHeading">Heading</div>%s<div>v1.0.0</div></div>
+<div data-test-id="UnitHeader-licenses"><div>%s</div></div>
+<div class="UnitMeta"><ul>%s</ul></div>
+</html>`, banners, kinds, license, checks)
+}
+
+// auditVersionsPage returns a versions page with the given versions.
+func auditVersionsPage(versions ...string) string {
+	tags := ""
+	for _, v := range versions {
+		tags += fmt.Sprintf(`<div class="Version-tag"><a class="js-versionLink">%s</a></div><div class="Version-commitTime">Jan 2, 2000</div>`, v)
+	}
+	return `<html><div class="Versions-list">` + tags + `</div></html>`
+}
+
+func TestClient_Audit(t *testing.T) {
+	allPassed := map[string]bool{"Valid go.mod file": true, "Redistributable license": true, "Tagged version": true, "Stable version": true}
+	pages := map[string]string{
+		"/example.com/good@v1.2.0": auditPackagePage("MIT", allPassed, ""),
+		"/example.com/good":        auditVersionsPage("v1.2.0", "v1.1.0"),
+		"/example.com/old@v1.0.0":  auditPackagePage("BSD-3-Clause", allPassed, ""),
+		"/example.com/old":         auditVersionsPage("v1.2.0-rc.1", "v1.1.0", "v1.0.0", "v0.9.0"),
+		"/example.com/bad@v0.0.0-20210101000000-abcdefabcdef": auditPackagePage("proprietary", nil, `
+<div class="UnitHeader-banner UnitHeader-banner--deprecated"><span class="UnitHeader-bannerContent">Deprecated: use example.com/new instead.</span></div>
+<div class="UnitHeader-banner UnitHeader-banner--majorVersion"><span class="UnitHeader-bannerContent">The highest tagged major version is <a href="/example.com/bad/v2">v2</a>.</span></div>`),
+		"/example.com/bad": auditVersionsPage("v0.0.0-20210101000000-abcdefabcdef"),
+		// a module without a package at its root
+		"/example.com/tools@v0.1.0": auditUnitPage("<div>module</div>", "BSD-3-Clause", allPassed, ""),
+		"/example.com/tools":        auditVersionsPage("v0.1.0"),
+	}
+	goMod := `module example.com/mod
+
+go 1.17
+
+require (
+	example.com/good v1.2.0
+	example.com/old v1.0.0 // indirect
+	example.com/bad v0.0.0-20210101000000-abcdefabcdef
+	example.com/private v1.0.0
+	example.com/tools v0.1.0
+)
+`
+	goSum := `example.com/good v1.2.0 h1:abc=
+example.com/tools v0.1.0 h1:abc=
+example.com/good v1.2.0/go.mod h1:abc=
+example.com/bad v0.0.0-20210101000000-abcdefabcdef/go.mod h1:abc=
+example.com/private v1.0.0 h1:abc=
+`
+	withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			rw.WriteHeader(404)
+			return
+		}
+		rw.Write([]byte(page))
+	}, func(addr string) {
+		client := New(WithBaseURL("http://" + addr))
+		report, err := client.Audit(AuditRequest{GoMod: []byte(goMod), GoSum: []byte(goSum)})
+		assert.NoError(t, err)
+		assert.Equal(t, "example.com/mod", report.Module)
+		assert.Len(t, report.Modules, 5)
+
+		good := report.Modules[0]
+		assert.Equal(t, "example.com/good", good.Path)
+		assert.NoError(t, good.Err)
+		assert.Equal(t, "MIT", good.License)
+		assert.Equal(t, "v1.2.0", good.LatestVersion)
+		assert.Empty(t, good.Findings)
+
+		old := report.Modules[1]
+		assert.NoError(t, old.Err)
+		assert.True(t, old.Indirect)
+		assert.Equal(t, "v1.1.0", old.LatestVersion)
+		assert.Equal(t, []Finding{
+			{Kind: FindingMissingChecksum, Message: "no checksum in go.sum"},
+			{Kind: FindingOutdated, Message: "v1.0.0 is behind the latest version v1.1.0"},
+		}, old.Findings)
+
+		bad := report.Modules[2]
+		assert.NoError(t, bad.Err)
+		assert.Equal(t, "", bad.LatestVersion)
+		assert.Equal(t, []Finding{
+			{Kind: FindingNonRedistributableLicense, Message: "license 'proprietary' is not redistributable"},
+			{Kind: FindingNoTaggedVersion, Message: "module has no tagged version"},
+			{Kind: FindingNoStableVersion, Message: "module has no stable version"},
+			{Kind: FindingInvalidGoMod, Message: "module has no valid go.mod file"},
+			{Kind: FindingDeprecated, Message: "module is deprecated: use example.com/new instead."},
+			{Kind: FindingNewMajorVersion, Message: "a newer major version is available at example.com/bad/v2"},
+		}, bad.Findings)
+
+		private := report.Modules[3]
+		assert.ErrorIs(t, private.Err, ErrNotFound)
+		assert.Empty(t, private.Findings)
+
+		tools := report.Modules[4]
+		assert.NoError(t, tools.Err)
+		assert.Equal(t, "BSD-3-Clause", tools.License)
+		assert.Empty(t, tools.Findings)
+	})
+}
+
+func TestClient_AuditSkipIndirect(t *testing.T) {
+	client := New(WithBaseURL("http://127.0.0.1:0"))
+	report, err := client.AuditContext(context.Background(), AuditRequest{
+		GoMod:        []byte("module example.com/mod\nrequire example.com/dep v1.0.0 // indirect\n"),
+		SkipIndirect: true,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, report.Modules)
+}
+
+func TestClient_AuditInvalidGoMod(t *testing.T) {
+	client := New(WithBaseURL("http://127.0.0.1:0"))
+	_, err := client.Audit(AuditRequest{GoMod: []byte("require example.com/dep v1.0.0\n")})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "parsing go.mod: no module directive")
+
+	_, err = client.Audit(AuditRequest{GoMod: []byte("module example.com/mod\nrequire example.com/dep latest\n")})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "parsing go.mod: go.mod:2:")
+}
+
+func TestClient_AuditReplace(t *testing.T) {
+	allPassed := map[string]bool{"Valid go.mod file": true, "Redistributable license": true, "Tagged version": true, "Stable version": true}
+	pages := map[string]string{
+		"/example.com/fork@v1.1.0":   auditPackagePage("MIT", allPassed, ""),
+		"/example.com/fork":          auditVersionsPage("v1.2.0", "v1.1.0"),
+		"/example.com/pinned@v1.0.1": auditPackagePage("MIT", allPassed, ""),
+		"/example.com/pinned":        auditVersionsPage("v1.0.1", "v1.0.0"),
+	}
+	goMod := `module example.com/mod
+
+require(
+	example.com/forked v1.0.0
+	example.com/pinned v1.0.0
+	example.com/local v1.0.0
+	example.com/other v1.0.0
+)
+
+replace example.com/forked => example.com/fork v1.1.0
+
+replace (
+	example.com/pinned => example.com/pinned v1.0.2
+	example.com/pinned v1.0.0 => example.com/pinned v1.0.1
+	example.com/local v1.0.0 => ../local
+	example.com/other v0.9.0 => ../other
+)
+`
+	goSum := `example.com/fork v1.1.0 h1:abc=
+example.com/pinned v1.0.1 h1:abc=
+`
+	var requested []string
+	withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			rw.WriteHeader(404)
+			return
+		}
+		rw.Write([]byte(page))
+	}, func(addr string) {
+		client := New(WithBaseURL("http://" + addr))
+		report, err := client.Audit(AuditRequest{GoMod: []byte(goMod), GoSum: []byte(goSum), Concurrency: 1})
+		assert.NoError(t, err)
+		assert.Len(t, report.Modules, 4)
+
+		forked := report.Modules[0]
+		assert.NoError(t, forked.Err)
+		assert.Equal(t, "example.com/forked", forked.Path)
+		assert.Equal(t, "example.com/fork", forked.ReplacePath)
+		assert.Equal(t, "v1.1.0", forked.ReplaceVersion)
+		assert.Equal(t, "v1.2.0", forked.LatestVersion)
+		assert.Equal(t, []Finding{
+			{Kind: FindingOutdated, Message: "v1.1.0 is behind the latest version v1.2.0"},
+		}, forked.Findings)
+
+		pinned := report.Modules[1]
+		assert.NoError(t, pinned.Err)
+		assert.Equal(t, "v1.0.1", pinned.ReplaceVersion)
+		assert.Empty(t, pinned.Findings)
+
+		local := report.Modules[2]
+		assert.NoError(t, local.Err)
+		assert.Equal(t, "../local", local.ReplacePath)
+		assert.Equal(t, "", local.ReplaceVersion)
+		assert.Equal(t, []Finding{
+			{Kind: FindingReplacedByDirectory, Message: "replaced by the local directory '../local', which isn't audited"},
+		}, local.Findings)
+
+		other := report.Modules[3]
+		assert.Equal(t, "", other.ReplacePath)
+		assert.ErrorIs(t, other.Err, ErrNotFound)
+		assert.Equal(t, []Finding{
+			{Kind: FindingMissingChecksum, Message: "no checksum in go.sum"},
+		}, other.Findings)
+	})
+	assert.NotContains(t, requested, "/example.com/local@v1.0.0")
+}
+
+func TestModuleAudit_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(ModuleAudit{Path: "example.com/a", Version: "v1.0.0", Err: ErrNotFound})
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"Path":"example.com/a"`)
+	assert.Contains(t, string(b), `"Error":"`+ErrNotFound.Error()+`"`)
+
+	b, err = json.Marshal(ModuleAudit{Path: "example.com/a", Version: "v1.0.0"})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), `"Error"`)
+}
@@ -0,0 +1,103 @@
+package pkggodevclient
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultConcurrency is the number of requests that batches make at once, unless configured otherwise.
+const defaultConcurrency = 4
+
+type DescribePackageResult struct {
+	// Index is the index of the request in the batch
+	Index   int
+	Request DescribePackageRequest
+	Package *Package
+	Err     error
+}
+
+type VersionsResult struct {
+	// Index is the index of the request in the batch
+	Index    int
+	Request  VersionsRequest
+	Versions *Versions
+	Err      error
+}
+
+type ImportedByResult struct {
+	// Index is the index of the request in the batch
+	Index      int
+	Request    ImportedByRequest
+	ImportedBy *ImportedBy
+	Err        error
+}
+
+// DescribePackageBatch describes many packages, making at most concurrency requests at once, or 4 if concurrency is not positive.
+// Results are sent on the returned channel as they complete, so they are not in the order of the requests.
+// A failed request doesn't affect the others, and its error is in its result.
+// The channel is closed after all results are sent, or after ctx is done, so it may be closed without a result for every request.
+func (c *client) DescribePackageBatch(ctx context.Context, reqs []DescribePackageRequest, concurrency int) <-chan DescribePackageResult {
+	return sendBatch(ctx, len(reqs), concurrency, func(i int) DescribePackageResult {
+		p, err := c.DescribePackageContext(ctx, reqs[i])
+		return DescribePackageResult{Index: i, Request: reqs[i], Package: p, Err: err}
+	})
+}
+
+// VersionsBatch gets the versions of many packages, like DescribePackageBatch.
+func (c *client) VersionsBatch(ctx context.Context, reqs []VersionsRequest, concurrency int) <-chan VersionsResult {
+	return sendBatch(ctx, len(reqs), concurrency, func(i int) VersionsResult {
+		v, err := c.VersionsContext(ctx, reqs[i])
+		return VersionsResult{Index: i, Request: reqs[i], Versions: v, Err: err}
+	})
+}
+
+// ImportedByBatch gets the importers of many packages, like DescribePackageBatch.
+func (c *client) ImportedByBatch(ctx context.Context, reqs []ImportedByRequest, concurrency int) <-chan ImportedByResult {
+	return sendBatch(ctx, len(reqs), concurrency, func(i int) ImportedByResult {
+		importedBy, err := c.ImportedByContext(ctx, reqs[i])
+		return ImportedByResult{Index: i, Request: reqs[i], ImportedBy: importedBy, Err: err}
+	})
+}
+
+// sendBatch runs f like runBatch, and sends the result of each call on the returned channel.
+// The channel is closed after all results are sent, or after ctx is done.
+func sendBatch[T any](ctx context.Context, n int, concurrency int, f func(i int) T) <-chan T {
+	results := make(chan T)
+	go func() {
+		defer close(results)
+		runBatch(ctx, n, concurrency, func(i int) {
+			result := f(i)
+			select {
+			case results <- result:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return results
+}
+
+// runBatch calls f with each index from 0 to n-1, with at most concurrency calls at once,
+// and returns after all calls return. It stops making calls once ctx is done.
+func runBatch(ctx context.Context, n int, concurrency int, f func(i int)) {
+	if concurrency < 1 {
+		concurrency = defaultConcurrency
+	}
+	wg := sync.WaitGroup{}
+	sem := make(chan struct{}, concurrency)
+	for i := 0; i < n && ctx.Err() == nil; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			f(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,162 @@
+package pkggodevclient
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ImportedByBatch(t *testing.T) {
+	withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+		pkg := strings.TrimPrefix(r.URL.Path, "/")
+		if pkg == "missing" {
+			rw.WriteHeader(404)
+			return
+		}
+		rw.Write([]byte(`<html><div class="u-breakWord">` + pkg + `-importer</div></html>`))
+	}, func(addr string) {
+		client := New(WithBaseURL("http://" + addr))
+		reqs := []ImportedByRequest{{Package: "foo"}, {Package: "missing"}, {Package: "bar"}}
+
+		results := map[int]ImportedByResult{}
+		for result := range client.ImportedByBatch(context.Background(), reqs, 2) {
+			results[result.Index] = result
+		}
+
+		assert.Len(t, results, 3)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, "foo", results[0].Request.Package)
+		assert.Equal(t, []string{"foo-importer"}, results[0].ImportedBy.ImportedBy)
+		assert.ErrorIs(t, results[1].Err, ErrNotFound)
+		assert.Nil(t, results[1].ImportedBy)
+		assert.NoError(t, results[2].Err)
+		assert.Equal(t, []string{"bar-importer"}, results[2].ImportedBy.ImportedBy)
+	})
+}
+
+func TestClient_DescribePackageBatch(t *testing.T) {
+	withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+		pkg := strings.TrimPrefix(r.URL.Path, "/")
+		if pkg == "broken" {
+			rw.WriteHeader(500)
+			return
+		}
+		rw.Write([]byte(`<html>
+<div class="UnitHeader-titleHeading">Heading</div><div>package</div><div>module</div>
+<div data-test-id="UnitHeader-version"><div>Version: v1.0.0</div></div>
+</html>`))
+	}, func(addr string) {
+		client := New(WithBaseURL("http://" + addr))
+		reqs := []DescribePackageRequest{{Package: "foo"}, {Package: "broken"}, {Package: "bar"}}
+
+		results := map[int]DescribePackageResult{}
+		for result := range client.DescribePackageBatch(context.Background(), reqs, 0) {
+			results[result.Index] = result
+		}
+
+		assert.Len(t, results, 3)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, "foo", results[0].Package.Package)
+		assert.Equal(t, "v1.0.0", results[0].Package.Version)
+		assert.Contains(t, results[1].Err.Error(), "Internal Server Error")
+		assert.Nil(t, results[1].Package)
+		assert.Equal(t, "broken", results[1].Request.Package)
+		assert.NoError(t, results[2].Err)
+		assert.Equal(t, "bar", results[2].Package.Package)
+	})
+}
+
+func TestClient_VersionsBatch(t *testing.T) {
+	withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+		pkg := strings.TrimPrefix(r.URL.Path, "/")
+		if pkg == "missing" {
+			rw.WriteHeader(404)
+			return
+		}
+		rw.Write([]byte(`<html><div class="Versions-list">
+<div class="Version-tag"><a class="js-versionLink">v1.0.0</a></div><div class="Version-commitTime">Jan 2, 2000</div>
+</div></html>`))
+	}, func(addr string) {
+		client := New(WithBaseURL("http://" + addr))
+		reqs := []VersionsRequest{{Package: "missing"}, {Package: "foo"}}
+
+		results := map[int]VersionsResult{}
+		for result := range client.VersionsBatch(context.Background(), reqs, 2) {
+			results[result.Index] = result
+		}
+
+		assert.Len(t, results, 2)
+		assert.ErrorIs(t, results[0].Err, ErrNotFound)
+		assert.Nil(t, results[0].Versions)
+		assert.NoError(t, results[1].Err)
+		assert.Equal(t, "foo", results[1].Versions.Package)
+		assert.Equal(t, "v1.0.0", results[1].Versions.Versions[0].FullVersion)
+	})
+}
+
+func TestClient_BatchCancellation(t *testing.T) {
+	var reqs int32
+	withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&reqs, 1)
+		// the requests after the first hang until the client gives up on them
+		if r.URL.Path != "/a" {
+			<-r.Context().Done()
+			return
+		}
+		rw.Write([]byte(`<html><div class="Versions-list"></div></html>`))
+	}, func(addr string) {
+		client := New(WithBaseURL("http://" + addr))
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		batchReqs := []VersionsRequest{{Package: "a"}, {Package: "b"}, {Package: "c"}, {Package: "d"}, {Package: "e"}}
+
+		results := client.VersionsBatch(ctx, batchReqs, 1)
+		first := <-results
+		assert.NoError(t, first.Err)
+		cancel()
+		// the channel is closed after the in-flight request, which is canceled
+		n := 1
+		for result := range results {
+			n++
+			assert.ErrorIs(t, result.Err, ErrCanceled)
+		}
+		assert.Less(t, n, len(batchReqs))
+		assert.LessOrEqual(t, atomic.LoadInt32(&reqs), int32(2))
+	})
+}
+
+func TestRunBatch(t *testing.T) {
+	t.Run("limits concurrency", func(t *testing.T) {
+		var running, maxRunning int32
+		var calls []int
+		mut := sync.Mutex{}
+		runBatch(context.Background(), 10, 3, func(i int) {
+			n := atomic.AddInt32(&running, 1)
+			mut.Lock()
+			calls = append(calls, i)
+			if n > maxRunning {
+				maxRunning = n
+			}
+			mut.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		})
+		assert.Len(t, calls, 10)
+		assert.LessOrEqual(t, maxRunning, int32(3))
+	})
+	t.Run("stops making calls when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var calls int32
+		runBatch(ctx, 10, 1, func(i int) {
+			atomic.AddInt32(&calls, 1)
+			cancel()
+		})
+		assert.Equal(t, int32(1), calls)
+	})
+}
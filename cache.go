@@ -0,0 +1,184 @@
+package pkggodevclient
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores pkg.go.dev responses, keyed by URL.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value for key, if it is present and hasn't expired.
+	Get(key string) ([]byte, bool)
+	// Set stores the value for key, expiring it after ttl.
+	Set(key string, value []byte, ttl time.Duration)
+	Delete(key string)
+}
+
+// Endpoint identifies a kind of pkg.go.dev page, for per-endpoint cache TTLs.
+// EndpointGoMod is the exception, which identifies go.mod files from the module proxy.
+type Endpoint string
+
+const (
+	EndpointPackage    Endpoint = "package"
+	EndpointVersions   Endpoint = "versions"
+	EndpointImportedBy Endpoint = "importedby"
+	EndpointImports    Endpoint = "imports"
+	EndpointLicenses   Endpoint = "licenses"
+	EndpointSearch     Endpoint = "search"
+	EndpointGoMod      Endpoint = "gomod"
+)
+
+type refreshKey struct{}
+
+// RefreshCache returns a context that makes requests skip cached responses.
+// Fresh responses still replace the cached ones, so this can be used to force a refresh of a single package.
+func RefreshCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, refreshKey{}, true)
+}
+
+func shouldRefresh(ctx context.Context) bool {
+	refresh, _ := ctx.Value(refreshKey{}).(bool)
+	return refresh
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// MemoryCache is an in-memory Cache that evicts the least recently used entries once it is full.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	// lru is ordered from most to least recently used
+	lru *list.List
+	now func() time.Time
+}
+
+// NewMemoryCache returns a MemoryCache that holds up to maxEntries entries.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+		now:        time.Now,
+	}
+}
+
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*memoryCacheEntry)
+	if !c.now().Before(entry.expires) {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return entry.value, true
+}
+
+func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &memoryCacheEntry{key: key, value: value, expires: c.now().Add(ttl)}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+	}
+}
+
+// FileCache is a Cache that stores each entry in its own file in a directory.
+// Errors reading or writing files are treated as cache misses.
+type FileCache struct {
+	dir string
+	now func() time.Time
+}
+
+// NewFileCache returns a FileCache that stores entries in dir, which is created if it doesn't exist.
+func NewFileCache(dir string) (*FileCache, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	return &FileCache{dir: dir, now: time.Now}, nil
+}
+
+func (c *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// entries are stored as the expiration time in Unix nanoseconds, a newline, and then the value
+func (c *FileCache) Get(key string) ([]byte, bool) {
+	b, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	i := strings.IndexByte(string(b), '\n')
+	if i == -1 {
+		return nil, false
+	}
+	expires, err := strconv.ParseInt(string(b[:i]), 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	if c.now().UnixNano() >= expires {
+		c.Delete(key)
+		return nil, false
+	}
+	return b[i+1:], true
+}
+
+func (c *FileCache) Set(key string, value []byte, ttl time.Duration) {
+	expires := strconv.FormatInt(c.now().Add(ttl).UnixNano(), 10)
+	// write to a temp file and rename it, so that concurrent readers never see a partial entry
+	f, err := os.CreateTemp(c.dir, "tmp-")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(append([]byte(expires+"\n"), value...))
+	closeErr := f.Close()
+	if err != nil || closeErr != nil {
+		os.Remove(f.Name())
+		return
+	}
+	if err := os.Rename(f.Name(), c.path(key)); err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+func (c *FileCache) Delete(key string) {
+	os.Remove(c.path(key))
+}
@@ -0,0 +1,117 @@
+package pkggodevclient
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testCache(t *testing.T, cache Cache, setNow func(time.Time)) {
+	now := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	setNow(now)
+
+	_, ok := cache.Get("foo")
+	assert.False(t, ok)
+
+	cache.Set("foo", []byte("bar"), time.Minute)
+	v, ok := cache.Get("foo")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("bar"), v)
+
+	cache.Set("foo", []byte("baz"), time.Minute)
+	v, ok = cache.Get("foo")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("baz"), v)
+
+	setNow(now.Add(time.Minute))
+	_, ok = cache.Get("foo")
+	assert.False(t, ok)
+
+	cache.Set("foo", []byte("bar"), time.Minute)
+	cache.Delete("foo")
+	_, ok = cache.Get("foo")
+	assert.False(t, ok)
+}
+
+func TestMemoryCache(t *testing.T) {
+	cache := NewMemoryCache(2)
+	testCache(t, cache, func(now time.Time) { cache.now = func() time.Time { return now } })
+
+	t.Run("evicts least recently used entries", func(t *testing.T) {
+		cache := NewMemoryCache(2)
+		cache.Set("a", []byte("a"), time.Hour)
+		cache.Set("b", []byte("b"), time.Hour)
+		cache.Get("a")
+		cache.Set("c", []byte("c"), time.Hour)
+
+		_, ok := cache.Get("a")
+		assert.True(t, ok)
+		_, ok = cache.Get("b")
+		assert.False(t, ok)
+		_, ok = cache.Get("c")
+		assert.True(t, ok)
+	})
+}
+
+func TestFileCache(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir())
+	assert.NoError(t, err)
+	testCache(t, cache, func(now time.Time) { cache.now = func() time.Time { return now } })
+}
+
+func TestClient_Cache(t *testing.T) {
+	var reqs int32
+	withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&reqs, 1)
+		if r.URL.Query().Get("tab") == "licenses" {
+			rw.WriteHeader(404)
+			return
+		}
+		rw.Write([]byte(`<html><div class="u-breakWord">foo</div></html>`))
+	}, func(addr string) {
+		client := New(
+			WithBaseURL("http://"+addr),
+			WithCache(NewMemoryCache(10), time.Hour),
+			WithCacheTTL(EndpointImports, 0),
+		)
+		expectReqs := func(n int32, f func()) {
+			t.Helper()
+			atomic.StoreInt32(&reqs, 0)
+			f()
+			assert.Equal(t, n, atomic.LoadInt32(&reqs))
+		}
+
+		expectReqs(1, func() {
+			importedBy, err := client.ImportedBy(ImportedByRequest{Package: "somepackage"})
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"foo"}, importedBy.ImportedBy)
+		})
+		// cached
+		expectReqs(0, func() {
+			importedBy, err := client.ImportedBy(ImportedByRequest{Package: "somepackage"})
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"foo"}, importedBy.ImportedBy)
+		})
+		// refreshed
+		expectReqs(1, func() {
+			_, err := client.ImportedByContext(RefreshCache(context.Background()), ImportedByRequest{Package: "somepackage"})
+			assert.NoError(t, err)
+		})
+		// caching is disabled for the endpoint
+		expectReqs(2, func() {
+			client.Imports(ImportsRequest{Package: "somepackage"})
+			client.Imports(ImportsRequest{Package: "somepackage"})
+		})
+		// errors aren't cached
+		expectReqs(2, func() {
+			_, err := client.Licenses(LicensesRequest{Package: "somepackage"})
+			assert.Contains(t, err.Error(), "not found on pkg.go.dev")
+			_, err = client.Licenses(LicensesRequest{Package: "somepackage"})
+			assert.Contains(t, err.Error(), "not found on pkg.go.dev")
+		})
+	})
+}
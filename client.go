@@ -1,35 +1,84 @@
 package pkggodevclient
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/gocolly/colly/v2"
+	"golang.org/x/time/rate"
 )
 
-type client struct {
-	httpClient *http.Client
-	baseURL    string
+// Client queries pkg.go.dev.
+// Each method has a variant that accepts a context, which is used for cancellation of the underlying requests.
+type Client interface {
+	ImportedBy(req ImportedByRequest) (*ImportedBy, error)
+	ImportedByContext(ctx context.Context, req ImportedByRequest) (*ImportedBy, error)
+	DescribePackage(req DescribePackageRequest) (*Package, error)
+	DescribePackageContext(ctx context.Context, req DescribePackageRequest) (*Package, error)
+	Versions(req VersionsRequest) (*Versions, error)
+	VersionsContext(ctx context.Context, req VersionsRequest) (*Versions, error)
+	Search(req SearchRequest) (*SearchResults, error)
+	SearchContext(ctx context.Context, req SearchRequest) (*SearchResults, error)
+	SearchIterator(ctx context.Context, req SearchRequest) SearchIterator
+	Imports(req ImportsRequest) (*Imports, error)
+	ImportsContext(ctx context.Context, req ImportsRequest) (*Imports, error)
+	Licenses(req LicensesRequest) ([]License, error)
+	LicensesContext(ctx context.Context, req LicensesRequest) ([]License, error)
+	Documentation(req DocumentationRequest) (*Documentation, error)
+	DocumentationContext(ctx context.Context, req DocumentationRequest) (*Documentation, error)
+	Examples(req ExamplesRequest) ([]Example, error)
+	ExamplesContext(ctx context.Context, req ExamplesRequest) ([]Example, error)
+	Directories(req DirectoriesRequest) ([]Directory, error)
+	DirectoriesContext(ctx context.Context, req DirectoriesRequest) ([]Directory, error)
+	Readme(req ReadmeRequest) (*Readme, error)
+	ReadmeContext(ctx context.Context, req ReadmeRequest) (*Readme, error)
+	DescribePackageBatch(ctx context.Context, reqs []DescribePackageRequest, concurrency int) <-chan DescribePackageResult
+	VersionsBatch(ctx context.Context, reqs []VersionsRequest, concurrency int) <-chan VersionsResult
+	ImportedByBatch(ctx context.Context, reqs []ImportedByRequest, concurrency int) <-chan ImportedByResult
+	Audit(req AuditRequest) (*AuditReport, error)
+	AuditContext(ctx context.Context, req AuditRequest) (*AuditReport, error)
 }
 
-var ErrNotFound = errors.New("not found on pkg.go.dev")
-
-type ErrorList struct {
-	Errs []error
+type client struct {
+	httpClient       *http.Client
+	baseURL          string
+	proxyURL         string
+	userAgent        string
+	respectRobotsTxt bool
+	// limiter and sem are shared by all requests made by the client
+	limiter *rate.Limiter
+	sem     chan struct{}
+	retry   RetryPolicy
+	cache   Cache
+	// cacheTTLs are the TTLs of endpoints that don't use defaultCacheTTL
+	cacheTTLs       map[Endpoint]time.Duration
+	defaultCacheTTL time.Duration
+	// now and location are used to resolve relative times such as "2 hours ago" into dates
+	now            func() time.Time
+	location       *time.Location
+	parseMode      ParseMode
+	warningHandler func(warning error)
 }
 
-func (e *ErrorList) Error() string {
-	return fmt.Sprintf("errors: %v", e.Errs)
-}
+// Option configures a client created by New.
+type Option func(c *client)
 
-func New(options ...func(c *client)) *client {
+func New(options ...Option) Client {
 	c := &client{
-		baseURL: "https://pkg.go.dev",
+		baseURL:   "https://pkg.go.dev",
+		proxyURL:  "https://proxy.golang.org",
+		userAgent: "pkggodev-client (https://github.com/guseggert/pkggodev-client)",
+		cacheTTLs: map[Endpoint]time.Duration{},
+		now:       time.Now,
+		location:  time.UTC,
+		parseMode: ParseModeDefault,
 	}
 	for _, opt := range options {
 		opt(c)
@@ -37,26 +86,168 @@ func New(options ...func(c *client)) *client {
 	return c
 }
 
-func WithBaseURL(url string) func(c *client) {
+func WithBaseURL(url string) Option {
 	return func(c *client) {
 		c.baseURL = url
 	}
 }
 
-func WithHTTPClient(httpClient *http.Client) func(c *client) {
+// WithProxyURL sets the URL of the module proxy, which is used for information that pkg.go.dev doesn't show.
+func WithProxyURL(url string) Option {
+	return func(c *client) {
+		c.proxyURL = url
+	}
+}
+
+// WithClock sets the function that returns the current time, which is used to resolve relative times such as "2 hours ago".
+// It defaults to time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(c *client) {
+		c.now = now
+	}
+}
+
+// WithTimezone sets the timezone that dates are in, which defaults to UTC.
+func WithTimezone(location *time.Location) Option {
+	return func(c *client) {
+		c.location = location
+	}
+}
+
+// WithParseMode sets how strictly pages are parsed, which defaults to ParseModeDefault.
+func WithParseMode(mode ParseMode) Option {
+	return func(c *client) {
+		c.parseMode = mode
+	}
+}
+
+// WithWarningHandler sets a function that is called with each parse or drift error that is ignored in ParseModeLenient,
+// and with errors of optional lookups, such as of Package.GoVersion, which are ignored in every mode.
+// It may be called concurrently.
+func WithWarningHandler(handler func(warning error)) Option {
+	return func(c *client) {
+		c.warningHandler = handler
+	}
+}
+
+func WithHTTPClient(httpClient *http.Client) Option {
 	return func(c *client) {
 		c.httpClient = httpClient
 	}
 }
 
-func (c *client) newCollector() *colly.Collector {
+// WithRateLimit limits the client to an average of requestsPerSecond requests, with bursts of up to burst requests.
+// The limit is shared by all methods of the client.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(c *client) {
+		if burst < 1 {
+			burst = 1
+		}
+		c.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+	}
+}
+
+// WithMaxParallelism limits the number of requests the client has in flight at once.
+// The limit is shared by all methods of the client.
+func WithMaxParallelism(n int) Option {
+	return func(c *client) {
+		if n < 1 {
+			n = 1
+		}
+		c.sem = make(chan struct{}, n)
+	}
+}
+
+// WithRetryPolicy makes the client retry requests that fail with a 429 or 5xx response, according to the policy.
+// By default, requests are not retried.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *client) {
+		c.retry = policy
+	}
+}
+
+// WithCache makes the client look up pages in the cache before fetching them, and cache successful responses for ttl.
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(c *client) {
+		c.cache = cache
+		c.defaultCacheTTL = ttl
+	}
+}
+
+// WithCacheTTL overrides the TTL of cached responses for the endpoint.
+// A TTL of zero disables caching for the endpoint.
+func WithCacheTTL(endpoint Endpoint, ttl time.Duration) Option {
+	return func(c *client) {
+		c.cacheTTLs[endpoint] = ttl
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with each request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRobotsTxt makes the client honor pkg.go.dev's robots.txt.
+// Requests for disallowed pages return an error wrapping colly.ErrRobotsTxtBlocked.
+func WithRobotsTxt() Option {
+	return func(c *client) {
+		c.respectRobotsTxt = true
+	}
+}
+
+func (c *client) newCollector(ctx context.Context, endpoint Endpoint) *colly.Collector {
 	col := colly.NewCollector()
+	col.UserAgent = c.userAgent
+	col.IgnoreRobotsTxt = !c.respectRobotsTxt
+	httpClient := &http.Client{}
 	if c.httpClient != nil {
-		col.SetClient(c.httpClient)
+		clientCopy := *c.httpClient
+		httpClient = &clientCopy
 	}
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &transport{
+		ctx:     ctx,
+		base:    base,
+		limiter: c.limiter,
+		sem:     c.sem,
+		retry:   c.retry,
+		cache:   c.cache,
+	}
+	if ttl, ok := c.cacheTTLs[endpoint]; ok {
+		t.cacheTTL = ttl
+	} else {
+		t.cacheTTL = c.defaultCacheTTL
+	}
+	httpClient.Transport = t
+	col.SetClient(httpClient)
 	return col
 }
 
+// visit visits the URL, returning any error that colly doesn't report to OnError callbacks,
+// such as an invalid URL or a URL that robots.txt disallows.
+func visit(col *colly.Collector, url string) error {
+	// colly returns the errors that it reports to OnError callbacks too, so they're tracked to not be reported twice
+	var reported []error
+	col.OnError(func(_ *colly.Response, err error) {
+		reported = append(reported, err)
+	})
+	err := col.Visit(url)
+	if err == nil {
+		return nil
+	}
+	for _, r := range reported {
+		if errors.Is(err, r) {
+			return nil
+		}
+	}
+	return fmt.Errorf("visiting %s: %w", url, err)
+}
+
 type ImportedByRequest struct {
 	Package string
 }
@@ -64,50 +255,116 @@ type ImportedByRequest struct {
 type ImportedBy struct {
 	Package    string
 	ImportedBy []string
+	// Total is the number of known importers according to pkg.go.dev, which can be more than it lists.
+	Total int
+	// Complete is true if ImportedBy contains all of the known importers.
+	Complete bool
 }
 
 func (c *client) ImportedBy(req ImportedByRequest) (*ImportedBy, error) {
-	col := c.newCollector()
+	return c.ImportedByContext(context.Background(), req)
+}
+
+func (c *client) ImportedByContext(ctx context.Context, req ImportedByRequest) (*ImportedBy, error) {
+	col := c.newCollector(ctx, EndpointImportedBy)
 	importedBy := &ImportedBy{Package: req.Package}
-	var err error
+	errs := &ErrorList{}
 
 	col.OnHTML(".u-breakWord", func(e *colly.HTMLElement) {
 		importedBy.ImportedBy = append(importedBy.ImportedBy, strings.TrimSpace(e.Text))
 	})
-	col.OnError(func(r *colly.Response, e error) {
-		if r.StatusCode == 404 {
-			err = ErrNotFound
+	// e.g. "Imported by: 1,234"
+	col.OnHTML("[data-test-id=UnitHeader-importedby]", func(e *colly.HTMLElement) {
+		text := strings.TrimSpace(e.Text)
+		totalStr := strings.ReplaceAll(strings.TrimSpace(strings.TrimPrefix(text, "Imported by:")), ",", "")
+		total, err := strconv.Atoi(totalStr)
+		if err != nil {
+			errs.Errs = append(errs.Errs, &ParseError{Package: req.Package, Selector: "[data-test-id=UnitHeader-importedby]", Err: fmt.Errorf("parsing importer count '%s': %w", text, err)})
 			return
 		}
-		err = fmt.Errorf("making req to %s: %w", r.Request.URL.String(), e)
+		importedBy.Total = total
+	})
+	// the list may be split across pages, so follow them all
+	col.OnHTML(".Pagination-next[href]", func(e *colly.HTMLElement) {
+		if err := visit(col, e.Request.AbsoluteURL(e.Attr("href"))); err != nil {
+			errs.Errs = append(errs.Errs, err)
+		}
+	})
+	c.expectElements(col, req.Package, errs, expectedElement{selector: "[data-test-id=UnitHeader-importedby]", textPrefix: "Imported by:"})
+	col.OnError(func(r *colly.Response, e error) {
+		errs.Errs = append(errs.Errs, c.responseError(r, e))
 	})
-	col.Visit(fmt.Sprintf("%s/%s?tab=importedby", c.baseURL, req.Package))
-	if err != nil {
+	if err := visit(col, fmt.Sprintf("%s/%s?tab=importedby", c.baseURL, req.Package)); err != nil {
+		errs.Errs = append(errs.Errs, err)
+	}
+	if err := ctxErr(ctx); err != nil {
 		return nil, err
 	}
+	c.handleParseErrors(errs)
+	if len(errs.Errs) != 0 {
+		return nil, errs
+	}
+	// the count is missing or stale, so the list is the best we know
+	if importedBy.Total < len(importedBy.ImportedBy) {
+		importedBy.Total = len(importedBy.ImportedBy)
+	}
+	importedBy.Complete = len(importedBy.ImportedBy) == importedBy.Total
 	return importedBy, nil
 }
 
 type DescribePackageRequest struct {
 	Package string
+	// ResolveGoVersion sets Package.GoVersion, which requires requests to the module proxy.
+	ResolveGoVersion bool
+	// AllowModule accepts a page that is a module but not a package, such as the root of a module without a root package.
+	AllowModule bool
 }
 
 type Package struct {
-	Package                   string
-	IsModule                  bool
-	IsPackage                 bool
-	Version                   string
+	Package   string
+	IsModule  bool
+	IsPackage bool
+	Version   string
+	// SemVer is the parsed Version, or nil if it is not a semantic version, such as for the standard library
+	SemVer                    *SemVer
 	Published                 string
+	PublishedTime             time.Time
 	License                   string
 	HasValidGoModFile         bool
 	HasRedistributableLicense bool
 	HasTaggedVersion          bool
 	HasStableVersion          bool
-	Repository                string
+	// Checks are the results of all the checks in the Details section by label, including ones without a field above
+	Checks map[string]bool
+	// CheckDetails explain the checks in Checks, by label
+	CheckDetails map[string]CheckDetail
+	Repository   string
+	// Deprecated is true if the module is deprecated, in which case DeprecationMessage is the reason
+	Deprecated         bool
+	DeprecationMessage string
+	// Retracted is true if this version of the module is retracted, in which case RetractionReason is the reason
+	Retracted        bool
+	RetractionReason string
+	// LatestMajorVersion is the path of the highest major version of the module, if it is higher than this one
+	LatestMajorVersion string
+	// GoVersion is the go directive of the module's go.mod file, which is only set if requested
+	GoVersion string
+}
+
+// CheckDetail explains a check in the Details section of a package's page.
+type CheckDetail struct {
+	// Tooltip is the explanation of the check
+	Tooltip string
+	// URL links to more information about the check
+	URL string
 }
 
 func (c *client) DescribePackage(req DescribePackageRequest) (*Package, error) {
-	col := c.newCollector()
+	return c.DescribePackageContext(context.Background(), req)
+}
+
+func (c *client) DescribePackageContext(ctx context.Context, req DescribePackageRequest) (*Package, error) {
+	col := c.newCollector(ctx, EndpointPackage)
 	p := &Package{Package: req.Package}
 	errs := &ErrorList{}
 
@@ -115,28 +372,49 @@ func (c *client) DescribePackage(req DescribePackageRequest) (*Package, error) {
 		versionStr := e.DOM.Children().First().Text()
 		version := strings.TrimSpace(strings.TrimPrefix(versionStr, "Version: "))
 		p.Version = version
+		p.SemVer = parseSemVer(version)
 	})
 	col.OnHTML("[data-test-id=UnitHeader-licenses]", func(e *colly.HTMLElement) {
 		licenseStr := e.DOM.Children().First().Text()
 		p.License = strings.TrimSpace(licenseStr)
 	})
-	// metadata are in a list, so parse it all at once
-	// TODO: add some extra validation here, so we error if things change order
+	// the checks are in a list, where each check is identified by its label, or by its details link if the label changed
 	col.OnHTML(".UnitMeta", func(e *colly.HTMLElement) {
-		lis := e.DOM.Find("li")
-		lis.Each(func(i int, s *goquery.Selection) {
-			checked := s.Find("img[alt=checked]").Length() > 0
-			switch i {
-			case 0:
-				p.HasValidGoModFile = checked
-			case 1:
-				p.HasRedistributableLicense = checked
-			case 2:
-				p.HasTaggedVersion = checked
-			case 3:
-				p.HasStableVersion = checked
+		p.Checks = map[string]bool{}
+		p.CheckDetails = map[string]CheckDetail{}
+		found := map[string]bool{}
+		e.DOM.Find("li").Has("img[alt=checked], img[alt=unchecked]").Each(func(i int, s *goquery.Selection) {
+			check := parseCheck(s)
+			if check.details.URL != "" {
+				// AbsoluteURL strips the fragment, so add it back
+				href := check.details.URL
+				check.details.URL = e.Request.AbsoluteURL(href)
+				if i := strings.Index(href, "#"); i != -1 {
+					check.details.URL += href[i:]
+				}
+			}
+			p.Checks[check.label] = check.passed
+			p.CheckDetails[check.label] = check.details
+			known, ok := findKnownCheck(check)
+			if !ok {
+				if c.checksDrift() {
+					errs.Errs = append(errs.Errs, &DriftError{Package: req.Package, Selector: ".UnitMeta li", Kind: DriftUnexpected, Found: check.label})
+				}
+				return
 			}
+			if !strings.EqualFold(check.label, known.label) && c.checksDrift() {
+				errs.Errs = append(errs.Errs, &DriftError{Package: req.Package, Selector: ".UnitMeta li", Kind: DriftUnexpected, Expected: known.label, Found: check.label})
+			}
+			found[known.label] = true
+			known.set(p, check.passed)
 		})
+		if c.checksDrift() {
+			for _, known := range knownChecks {
+				if !found[known.label] {
+					errs.Errs = append(errs.Errs, &DriftError{Package: req.Package, Selector: ".UnitMeta li", Kind: DriftMissing, Expected: known.label})
+				}
+			}
+		}
 	})
 	col.OnHTML(".UnitMeta-repo", func(e *colly.HTMLElement) {
 		text := e.DOM.Children().First().Text()
@@ -145,12 +423,26 @@ func (c *client) DescribePackage(req DescribePackageRequest) (*Package, error) {
 	col.OnHTML("[data-test-id=UnitHeader-commitTime]", func(e *colly.HTMLElement) {
 		text := strings.TrimSpace(e.Text)
 		dateStr := strings.TrimPrefix(text, "Published: ")
-		t, err := normalizeTime(dateStr)
+		t, err := c.parseDate(dateStr)
 		if err != nil {
-			errs.Errs = append(errs.Errs, err)
+			errs.Errs = append(errs.Errs, &ParseError{Package: req.Package, Selector: "[data-test-id=UnitHeader-commitTime]", Err: err})
 			return
 		}
-		p.Published = t
+		p.Published = t.Format(dateFormat)
+		p.PublishedTime = t
+	})
+	col.OnHTML(".UnitHeader-banner--deprecated", func(e *colly.HTMLElement) {
+		p.Deprecated = true
+		p.DeprecationMessage = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(e.Text), "Deprecated:"))
+	})
+	col.OnHTML(".UnitHeader-banner--retracted", func(e *colly.HTMLElement) {
+		p.Retracted = true
+		if m := retractionReasonRegexp.FindStringSubmatch(e.Text); m != nil {
+			p.RetractionReason = strings.TrimSpace(m[1])
+		}
+	})
+	col.OnHTML(".UnitHeader-banner--majorVersion a", func(e *colly.HTMLElement) {
+		p.LatestMajorVersion = directoryPath(e.Attr("href"))
 	})
 	col.OnHTML(".UnitHeader-titleHeading", func(e *colly.HTMLElement) {
 		for next := e.DOM.Next(); ; next = next.Next() {
@@ -166,28 +458,120 @@ func (c *client) DescribePackage(req DescribePackageRequest) (*Package, error) {
 				// so if we've gotten here and package=false then
 				// return an error since it probably means
 				// that we parsed incorrectly
-				if !p.IsPackage && !p.IsModule {
-					errs.Errs = append(errs.Errs, fmt.Errorf("IsPackage=false after parsing page for '%s', this probably indicates a parsing bug", req.Package))
+				if !p.IsPackage && !(p.IsModule && req.AllowModule) {
+					errs.Errs = append(errs.Errs, &ParseError{
+						Package:  req.Package,
+						Selector: ".UnitHeader-titleHeading",
+						Err:      fmt.Errorf("IsPackage=false after parsing page for '%s', this probably indicates a parsing bug", req.Package),
+					})
 				}
 				return
 			}
 		}
 	})
 
+	c.expectElements(col, req.Package, errs,
+		expectedElement{selector: ".UnitHeader-titleHeading"},
+		expectedElement{selector: "[data-test-id=UnitHeader-version]", textPrefix: "Version:"},
+		expectedElement{selector: "[data-test-id=UnitHeader-commitTime]", textPrefix: "Published:"},
+		expectedElement{selector: "[data-test-id=UnitHeader-licenses]"},
+		expectedElement{selector: ".UnitMeta"},
+		expectedElement{selector: ".UnitMeta-repo"},
+	)
 	col.OnError(func(r *colly.Response, e error) {
-		if r.StatusCode == 404 {
-			errs.Errs = append(errs.Errs, ErrNotFound)
-			return
-		}
-		errs.Errs = append(errs.Errs, fmt.Errorf("making req to %s: %w", r.Request.URL.String(), e))
+		errs.Errs = append(errs.Errs, c.responseError(r, e))
 	})
-	col.Visit(fmt.Sprintf("%s/%s", c.baseURL, req.Package))
+	if err := visit(col, fmt.Sprintf("%s/%s", c.baseURL, req.Package)); err != nil {
+		errs.Errs = append(errs.Errs, err)
+	}
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+	c.handleParseErrors(errs)
 	if len(errs.Errs) != 0 {
 		return nil, errs
 	}
+	// the standard library has no go.mod file, and the go version is optional, so failing to find it isn't an error
+	if req.ResolveGoVersion && p.SemVer != nil {
+		goVersion, err := c.goVersion(ctx, req.Package, p.Version)
+		if err != nil {
+			if err := ctxErr(ctx); err != nil {
+				return nil, err
+			}
+			c.warn(fmt.Errorf("resolving go version of '%s': %w", req.Package, err))
+		}
+		p.GoVersion = goVersion
+	}
 	return p, nil
 }
 
+// knownCheck is a check in the Details section of a package's page that has a field in Package.
+type knownCheck struct {
+	label string
+	// link is the end of the URL of the check's details link, which identifies the check if its label changed,
+	// unless other checks have the same link
+	link string
+	set  func(p *Package, passed bool)
+}
+
+var knownChecks = []knownCheck{
+	{label: "Valid go.mod file", link: "go.dev/ref/mod#go-mod-file", set: func(p *Package, passed bool) { p.HasValidGoModFile = passed }},
+	{label: "Redistributable license", link: "/license-policy", set: func(p *Package, passed bool) { p.HasRedistributableLicense = passed }},
+	{label: "Tagged version", link: "go.dev/doc/modules/version-numbers", set: func(p *Package, passed bool) { p.HasTaggedVersion = passed }},
+	{label: "Stable version", link: "go.dev/doc/modules/version-numbers", set: func(p *Package, passed bool) { p.HasStableVersion = passed }},
+}
+
+type check struct {
+	label   string
+	passed  bool
+	details CheckDetail
+}
+
+// parseCheck parses a list item of the Details section.
+// The label is either next to the tooltip, which is a details element, or in the tooltip's summary.
+func parseCheck(s *goquery.Selection) check {
+	c := check{passed: s.Find("img[alt=checked]").Length() > 0}
+	tooltip := s.Find("details").First()
+	withoutTooltip := s.Clone()
+	withoutTooltip.Find("details").Remove()
+	c.label = strings.Join(strings.Fields(withoutTooltip.Text()), " ")
+	if c.label == "" {
+		c.label = strings.Join(strings.Fields(tooltip.Find("summary").Text()), " ")
+	}
+	explanation := tooltip.Clone()
+	explanation.Find("summary").Remove()
+	c.details.Tooltip = strings.Join(strings.Fields(explanation.Text()), " ")
+	if href, ok := s.Find("a[href]").Last().Attr("href"); ok {
+		c.details.URL = href
+	}
+	return c
+}
+
+// findKnownCheck returns the known check with the check's label, or else the only one with its details link.
+func findKnownCheck(c check) (knownCheck, bool) {
+	for _, known := range knownChecks {
+		if strings.EqualFold(c.label, known.label) {
+			return known, true
+		}
+	}
+	if c.details.URL == "" {
+		return knownCheck{}, false
+	}
+	var matches []knownCheck
+	for _, known := range knownChecks {
+		if strings.HasSuffix(c.details.URL, known.link) {
+			matches = append(matches, known)
+		}
+	}
+	if len(matches) != 1 {
+		return knownCheck{}, false
+	}
+	return matches[0], true
+}
+
+// retractionReasonRegexp matches the rationale in the retraction banner, such as "This version has been retracted: reason".
+var retractionReasonRegexp = regexp.MustCompile(`(?is)retracted[^:]*:(.*)`)
+
 type Versions struct {
 	Package  string
 	Versions []Version
@@ -195,59 +579,86 @@ type Versions struct {
 type Version struct {
 	MajorVersion string
 	FullVersion  string
-	Date         string
+	// SemVer is the parsed FullVersion, or nil if it is not a semantic version, such as for the standard library
+	SemVer   *SemVer
+	Date     string
+	DateTime time.Time
+	// Changes are the symbols that were added in this version
+	Changes []Change
 }
 
-// TODO: parse the changes and wire them up to Version
 type Change struct {
-	URL            string
+	// URL links to the symbol's documentation
+	URL string
+	// Symbol is the name of the symbol, e.g. "Foo" or "Foo.Bar" for methods and fields
 	Symbol         string
 	SymbolSynopsis string
 }
 
-// normalizeTime normalizes a time string into a consistent format.
-// Times are generally represented as dates, so this only returns a date string, not a time string.
+// dateFormat is the format of the date strings, such as Package.Published.
+const dateFormat = "2006-01-02"
+
+// parseDate parses a time string into a date in the client's timezone.
+// Times are generally represented as dates, so this only returns the date at midnight, not the time.
 // It handles cases of durations as well like '1 hour ago' which are sometimes used (like in search results).
-// Parsed durations are returned as times relative to now.
-func normalizeTime(s string) (string, error) {
+// Parsed durations are returned as times relative to the client's clock.
+func (c *client) parseDate(s string) (time.Time, error) {
 	var absTime time.Time
+	now := c.now().In(c.location)
 
-	// as far as I can tell, the UI only uses "<quantity> hours ago" or "<quantity> days ago"
-	// e.g. "2 hours ago", "1 hours ago", "0 hours ago", "5 days ago", etc.
+	// the UI uses e.g. "today", "2 hours ago", "1 hour ago", "0 hours ago", "5 days ago", etc.
 	// at some point it switches back to an absolute date
 	// you can find examples at https://index.golang.org/index?since=2021-10-10T09:08:52.997264Z
 	if s == "today" {
-		absTime = time.Now()
-	} else if strings.Contains(s, "ago") {
-		now := time.Now()
+		absTime = now
+	} else if s == "yesterday" {
+		absTime = now.AddDate(0, 0, -1)
+	} else if strings.HasSuffix(s, " ago") {
 		// <quantity> <unit>[s] ago
 		split := strings.Split(s, " ")
+		if len(split) != 3 {
+			return time.Time{}, fmt.Errorf("parsing relative time '%s': expected '<quantity> <unit> ago'", s)
+		}
 		quantityStr := split[0]
-		quantity, err := strconv.ParseInt(quantityStr, 10, 64)
-		if err != nil {
-			return "", fmt.Errorf("parsing quantity '%s' of time '%s': %w", quantityStr, s, err)
+		var quantity int
+		if quantityStr == "a" || quantityStr == "an" {
+			quantity = 1
+		} else {
+			q, err := strconv.Atoi(quantityStr)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("parsing quantity '%s' of time '%s': %w", quantityStr, s, err)
+			}
+			quantity = q
 		}
 		quantityDur := time.Duration(quantity)
 		unit := strings.TrimSuffix(split[1], "s")
 
 		switch unit {
+		case "second":
+			absTime = now.Add(-quantityDur * time.Second)
+		case "minute":
+			absTime = now.Add(-quantityDur * time.Minute)
 		case "hour":
 			absTime = now.Add(-quantityDur * time.Hour)
 		case "day":
-			absTime = now.AddDate(0, 0, -int(quantity))
+			absTime = now.AddDate(0, 0, -quantity)
 		case "week":
-			absTime = now.AddDate(0, 0, -7*int(quantity))
+			absTime = now.AddDate(0, 0, -7*quantity)
+		case "month":
+			absTime = now.AddDate(0, -quantity, 0)
+		case "year":
+			absTime = now.AddDate(-quantity, 0, 0)
 		default:
-			return "", fmt.Errorf("unknown quantity '%s' when parsing '%s'", quantityStr, s)
+			return time.Time{}, fmt.Errorf("unknown unit '%s' when parsing '%s'", split[1], s)
 		}
 	} else {
-		d, err := time.Parse("Jan 2, 2006", s)
+		d, err := time.ParseInLocation("Jan 2, 2006", s, c.location)
 		if err != nil {
-			return "", fmt.Errorf("parsing date '%s': %w", s, err)
+			return time.Time{}, fmt.Errorf("parsing date '%s': %w", s, err)
 		}
 		absTime = d
 	}
-	return absTime.Format("2006-01-02"), nil
+	return time.Date(absTime.Year(), absTime.Month(), absTime.Day(), 0, 0, 0, 0, c.location), nil
 }
 
 type VersionsRequest struct {
@@ -255,8 +666,12 @@ type VersionsRequest struct {
 }
 
 func (c *client) Versions(req VersionsRequest) (*Versions, error) {
+	return c.VersionsContext(context.Background(), req)
+}
+
+func (c *client) VersionsContext(ctx context.Context, req VersionsRequest) (*Versions, error) {
 	//https://pkg.go.dev/github.com/ipfs/ipfs-cluster/ipfsconn/ipfshttp?tab=versions
-	col := c.newCollector()
+	col := c.newCollector(ctx, EndpointVersions)
 	errs := &ErrorList{}
 
 	versions := &Versions{Package: req.Package}
@@ -277,166 +692,156 @@ func (c *client) Versions(req VersionsRequest) (*Versions, error) {
 			if s.HasClass("Version-tag") {
 				version := s.Find(".js-versionLink").Text()
 				curVersion.FullVersion = version
+				curVersion.SemVer = parseSemVer(version)
 			}
 			// this means there are no changes, and it's the end of the entry
 			if s.HasClass("Version-commitTime") {
 				dateStr := strings.TrimSpace(s.Text())
-				t, err := normalizeTime(dateStr)
-				if err != nil {
-					errs.Errs = append(errs.Errs, err)
-					return
+				// keep the version without its date if the date can't be parsed, in case parsing is lenient
+				if t, err := c.parseDate(dateStr); err != nil {
+					errs.Errs = append(errs.Errs, &ParseError{Package: req.Package, Selector: ".Version-commitTime", Err: err})
+				} else {
+					curVersion.Date = t.Format(dateFormat)
+					curVersion.DateTime = t
 				}
-				curVersion.Date = t
 				versions.Versions = append(versions.Versions, curVersion)
 				curVersion = Version{}
 			}
 			// this means there are changes, and it's also the end of the entry
 			if s.HasClass("Version-details") {
 				dateStr := strings.TrimSpace(s.Find(".Version-summary").Text())
-				t, err := normalizeTime(dateStr)
-				if err != nil {
-					println("error in version details: " + err.Error())
-					return
+				// keep the version without its date if the date can't be parsed, in case parsing is lenient
+				if t, err := c.parseDate(dateStr); err != nil {
+					errs.Errs = append(errs.Errs, &ParseError{Package: req.Package, Selector: ".Version-summary", Err: err})
+				} else {
+					curVersion.Date = t.Format(dateFormat)
+					curVersion.DateTime = t
 				}
-				curVersion.Date = t
+
+				// each symbol links to its docs, where the URL fragment is the symbol name
+				s.Find(".Versions-symbol a").Each(func(i int, a *goquery.Selection) {
+					href, _ := a.Attr("href")
+					var symbol string
+					if hashIdx := strings.LastIndex(href, "#"); hashIdx != -1 {
+						symbol = href[hashIdx+1:]
+					}
+					if symbol == "" {
+						errs.Errs = append(errs.Errs, &ParseError{
+							Package:  req.Package,
+							Selector: ".Versions-symbol a",
+							Err:      fmt.Errorf("unable to find symbol name in link '%s' for version '%s'", href, curVersion.FullVersion),
+						})
+						return
+					}
+					curVersion.Changes = append(curVersion.Changes, Change{
+						// AbsoluteURL strips the fragment, so add it back
+						URL:            e.Request.AbsoluteURL(href) + "#" + symbol,
+						Symbol:         symbol,
+						SymbolSynopsis: strings.TrimSpace(a.Text()),
+					})
+				})
+
 				versions.Versions = append(versions.Versions, curVersion)
 				curVersion = Version{}
-
-				// TODO: parse the changes
 			}
 		})
 	})
 
+	c.expectElements(col, req.Package, errs, expectedElement{selector: ".Versions-list"})
 	col.OnError(func(r *colly.Response, e error) {
-		if r.StatusCode == 404 {
-			errs.Errs = append(errs.Errs, ErrNotFound)
-			return
-		}
-		errs.Errs = append(errs.Errs, fmt.Errorf("making req to %s: %w", r.Request.URL.String(), e))
+		errs.Errs = append(errs.Errs, c.responseError(r, e))
 	})
 
-	col.Visit(fmt.Sprintf("%s/%s?tab=versions", c.baseURL, req.Package))
+	if err := visit(col, fmt.Sprintf("%s/%s?tab=versions", c.baseURL, req.Package)); err != nil {
+		errs.Errs = append(errs.Errs, err)
+	}
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+	c.handleParseErrors(errs)
+	if len(errs.Errs) != 0 {
+		return nil, errs
+	}
 	return versions, nil
 }
 
-type SearchRequest struct {
-	Query string
-	Limit int
+type ImportsRequest struct {
+	Package string
 }
 
-type SearchResults struct {
-	Results []SearchResult
+type Imports struct {
+	Package string
+	// Imports are all of the non-standard library imports
+	Imports []string
+	// ModuleImports are the non-standard library imports, keyed by the module that provides them
+	ModuleImports          map[string][]string
+	StandardLibraryImports []string
 }
 
-type SearchResult struct {
-	Package    string
-	Version    string
-	Published  string
-	ImportedBy int
-	License    string
-	Synopsis   string
+func (c *client) Imports(req ImportsRequest) (*Imports, error) {
+	return c.ImportsContext(context.Background(), req)
 }
 
-func (c *client) Search(req SearchRequest) (*SearchResults, error) {
-	col := c.newCollector()
-	results := &SearchResults{}
+func (c *client) ImportsContext(ctx context.Context, req ImportsRequest) (*Imports, error) {
+	col := c.newCollector(ctx, EndpointImports)
+	imports := &Imports{
+		Package:       req.Package,
+		ModuleImports: map[string][]string{},
+	}
 	errs := &ErrorList{}
 
-	morePages := true
-
-	// on page n, compute if we should follow to page n+1
-	col.OnHTML("[data-test-id=results-total]", func(e *colly.HTMLElement) {
-		resultsStr := strings.TrimSpace(e.Text)
-		if resultsStr == "0 results" {
-			return
-		}
-		resultsSplit := strings.Split(resultsStr, " ")
-		if len(resultsSplit) == 2 {
-			// e.g. "1 result", "2 results", ...
-			morePages = false
-		} else {
-			// e.g. "1 - 25 of 125 results", "26-50 of 125 results", ...
-			upperBoundStr := resultsSplit[2]
-			upperBound, err := strconv.Atoi(upperBoundStr)
-			if err != nil {
-				errs.Errs = append(errs.Errs, err)
-				return
+	// the imports tab is a flat sequence of headings and lists,
+	// where a list belongs to the most recent heading before it
+	col.OnHTML(".Imports", func(e *colly.HTMLElement) {
+		var curModule string
+		inStdlib := false
+		e.DOM.Find("h2, .Imports-heading, .Imports-list").Each(func(i int, s *goquery.Selection) {
+			switch {
+			case s.Is("h2"):
+				inStdlib = strings.Contains(strings.ToLower(s.Text()), "standard library")
+				curModule = ""
+			case s.HasClass("Imports-heading"):
+				curModule = strings.TrimSpace(s.Text())
+			case s.HasClass("Imports-list"):
+				s.Find("li").Each(func(i int, li *goquery.Selection) {
+					importPath := strings.TrimSpace(li.Text())
+					if importPath == "" {
+						return
+					}
+					if inStdlib {
+						imports.StandardLibraryImports = append(imports.StandardLibraryImports, importPath)
+						return
+					}
+					if curModule == "" {
+						errs.Errs = append(errs.Errs, &ParseError{
+							Package:  req.Package,
+							Selector: ".Imports-heading",
+							Err:      fmt.Errorf("found import '%s' of '%s' without a module heading, this probably indicates a parsing bug", importPath, req.Package),
+						})
+						return
+					}
+					imports.Imports = append(imports.Imports, importPath)
+					imports.ModuleImports[curModule] = append(imports.ModuleImports[curModule], importPath)
+				})
 			}
-
-			totalResultsStr := resultsSplit[4]
-
-			reachedReqLimit := upperBound >= req.Limit
-			reachedLastPage := upperBoundStr == totalResultsStr
-			morePages = !reachedReqLimit && !reachedLastPage
-		}
+		})
 	})
 
-	col.OnHTML(".LegacySearchSnippet", func(e *colly.HTMLElement) {
-		if len(results.Results) == req.Limit {
-			return
-		}
-
-		pkg := strings.TrimSpace(e.DOM.Find("[data-test-id=snippet-title]").Text())
-		synopsis := strings.TrimSpace(e.DOM.Find(".SearchSnippet-synopsis").Text())
-		info := e.DOM.Find(".SearchSnippet-infoLabel")
-
-		// pseudoversions are truncated and contain '...', so we have to do an additional lookup
-		// it is of the format "v0.0.0-...-<hash>" so we can be confident in the lookup
-		// for now we just leave version blank if it's a pseudoversion, so searches don't take a long time
-		// we can add a flag to turn on version info later
-		// TODO: add flag to turn on inclusion of full pseudoversions
-		version := strings.TrimSpace(info.Find("[data-test-id=snippet-version]").Text())
-
-		publishedDateStr := strings.TrimSpace(info.Find("[data-test-id=snippet-published]").Text())
-		published, err := normalizeTime(publishedDateStr)
-		if err != nil {
-			errs.Errs = append(errs.Errs, err)
-			return
-		}
-		importedByWithCommas := strings.TrimSpace(info.Find("[data-test-id=snippet-importedby]").Text())
-		importedByStr := strings.ReplaceAll(importedByWithCommas, ",", "")
-		importedBy, err := strconv.Atoi(importedByStr)
-		if err != nil {
-			errs.Errs = append(errs.Errs, err)
-			return
-		}
-		license := strings.TrimSpace(info.Find("[data-test-id=snippet-license]").Text())
-		result := SearchResult{
-			Package:    pkg,
-			Synopsis:   synopsis,
-			Version:    version,
-			Published:  published,
-			ImportedBy: importedBy,
-			License:    license,
-		}
-		results.Results = append(results.Results, result)
-	})
+	c.expectElements(col, req.Package, errs, expectedElement{selector: ".Imports"})
 	col.OnError(func(r *colly.Response, e error) {
-		errs.Errs = append(errs.Errs, e)
+		errs.Errs = append(errs.Errs, c.responseError(r, e))
 	})
-	for page := 1; morePages; page++ {
-		col.Visit(fmt.Sprintf("%s/search?q=%s&m=package&page=%d", c.baseURL, req.Query, page))
-		if len(errs.Errs) > 0 {
-			return nil, errs
-		}
+	if err := visit(col, fmt.Sprintf("%s/%s?tab=imports", c.baseURL, req.Package)); err != nil {
+		errs.Errs = append(errs.Errs, err)
 	}
-
-	return results, nil
-}
-
-type ImportsRequest struct {
-	Package string
-}
-
-type Imports struct {
-	Package                string
-	Imports                []string
-	ModuleImports          map[string][]string
-	StandardLibraryImports []string
-}
-
-func (c *client) Imports(req ImportsRequest) (*Imports, error) {
-	return nil, nil
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+	c.handleParseErrors(errs)
+	if len(errs.Errs) != 0 {
+		return nil, errs
+	}
+	return imports, nil
 }
 
 type LicensesRequest struct {
@@ -444,11 +849,59 @@ type LicensesRequest struct {
 }
 
 type License struct {
-	Name     string
+	// Name is the license type as detected by pkg.go.dev, e.g. "MIT".
+	// If multiple licenses were detected in the same file, they are comma-separated.
+	Name string
+	// Source is the path of the file the license was found in, relative to the module root
 	Source   string
 	FullText string
 }
 
 func (c *client) Licenses(req LicensesRequest) ([]License, error) {
-	return nil, nil
+	return c.LicensesContext(context.Background(), req)
+}
+
+func (c *client) LicensesContext(ctx context.Context, req LicensesRequest) ([]License, error) {
+	col := c.newCollector(ctx, EndpointLicenses)
+	var licenses []License
+	errs := &ErrorList{}
+
+	col.OnHTML(".License", func(e *colly.HTMLElement) {
+		name := strings.TrimSpace(e.DOM.Find("h2").First().Text())
+		if name == "" {
+			errs.Errs = append(errs.Errs, &ParseError{
+				Package:  req.Package,
+				Selector: ".License h2",
+				Err:      fmt.Errorf("found license without a name for '%s', this probably indicates a parsing bug", req.Package),
+			})
+			return
+		}
+		source := strings.TrimSpace(e.DOM.Find(".License-source").Text())
+		source = strings.TrimSpace(strings.TrimPrefix(source, "Source:"))
+		licenses = append(licenses, License{
+			Name:     name,
+			Source:   source,
+			FullText: e.DOM.Find(".License-contents").Text(),
+		})
+	})
+
+	// the header only links to the licenses if the module has any
+	c.expectElements(col, req.Package, errs,
+		expectedElement{selector: "[data-test-id=UnitHeader-licenses]"},
+		expectedElement{selector: ".License", ifPresent: "[data-test-id=UnitHeader-licenses] a"},
+	)
+	col.OnError(func(r *colly.Response, e error) {
+		errs.Errs = append(errs.Errs, c.responseError(r, e))
+	})
+	if err := visit(col, fmt.Sprintf("%s/%s?tab=licenses", c.baseURL, req.Package)); err != nil {
+		errs.Errs = append(errs.Errs, err)
+	}
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+	c.handleParseErrors(errs)
+	if len(errs.Errs) != 0 {
+		return nil, errs
+	}
+	return licenses, nil
 }
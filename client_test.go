@@ -1,11 +1,14 @@
 package pkggodevclient
 
 import (
+	"context"
 	"errors"
 	"net"
 	"net/http"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -50,6 +53,8 @@ func TestClient_ImportedBy(t *testing.T) {
 		html              string
 		httpErrCode       int
 		expectImports     []string
+		expectTotal       int
+		expectComplete    bool
 		expectErrContains string
 	}{
 		{
@@ -61,12 +66,33 @@ func TestClient_ImportedBy(t *testing.T) {
 <div class="u-breakWord">bar</div>
 </body></html>
 `,
-			expectImports: []string{"foo", "bar"},
+			expectImports:  []string{"foo", "bar"},
+			expectTotal:    2,
+			expectComplete: true,
 		},
 		{
-			name:          "no results",
-			html:          "",
-			expectImports: nil,
+			name: "more importers than are listed",
+			html: `
+<html><body>
+<div data-test-id="UnitHeader-importedby">Imported by: 1,234</div>
+<div class="u-breakWord">foo</div>
+<div class="u-breakWord">bar</div>
+</body></html>
+`,
+			expectImports:  []string{"foo", "bar"},
+			expectTotal:    1234,
+			expectComplete: false,
+		},
+		{
+			name:           "no results",
+			html:           "",
+			expectImports:  nil,
+			expectComplete: true,
+		},
+		{
+			name:              "returns an error if the importer count can't be parsed",
+			html:              `<html><div data-test-id="UnitHeader-importedby">Imported by: lots</div></html>`,
+			expectErrContains: "parsing importer count 'Imported by: lots'",
 		},
 		{
 			name:              "http error returns an error",
@@ -98,16 +124,103 @@ func TestClient_ImportedBy(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, "somepackage", importedBy.Package)
 				assert.Equal(t, c.expectImports, importedBy.ImportedBy)
+				assert.Equal(t, c.expectTotal, importedBy.Total)
+				assert.Equal(t, c.expectComplete, importedBy.Complete)
 			})
 		})
 	}
 }
 
+func TestClient_ImportedByPagination(t *testing.T) {
+	pages := map[string]string{
+		"": `<html>
+<div data-test-id="UnitHeader-importedby">Imported by: 3</div>
+<div class="u-breakWord">foo</div>
+<div class="u-breakWord">bar</div>
+<a class="Pagination-next" href="/somepackage?tab=importedby&page=2">Next</a>
+</html>`,
+		"2": `<html>
+<div data-test-id="UnitHeader-importedby">Imported by: 3</div>
+<div class="u-breakWord">baz</div>
+</html>`,
+	}
+	withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte(pages[r.URL.Query().Get("page")]))
+	}, func(addr string) {
+		client := New(WithBaseURL("http://" + addr))
+		importedBy, err := client.ImportedBy(ImportedByRequest{Package: "somepackage"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"foo", "bar", "baz"}, importedBy.ImportedBy)
+		assert.Equal(t, 3, importedBy.Total)
+		assert.True(t, importedBy.Complete)
+	})
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestClient_ParseDate(t *testing.T) {
+	// just after midnight in UTC, which is still the previous day in New York
+	now := time.Date(2021, 3, 31, 0, 30, 0, 0, time.UTC)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("loading timezone: %s", err)
+	}
+
+	cases := []struct {
+		s                 string
+		location          *time.Location
+		expectErrContains string
+		expectDate        time.Time
+	}{
+		{s: "today", expectDate: date(2021, 3, 31)},
+		{s: "today", location: newYork, expectDate: time.Date(2021, 3, 30, 0, 0, 0, 0, newYork)},
+		{s: "yesterday", expectDate: date(2021, 3, 30)},
+		{s: "30 seconds ago", expectDate: date(2021, 3, 31)},
+		{s: "45 minutes ago", expectDate: date(2021, 3, 30)},
+		{s: "1 hour ago", expectDate: date(2021, 3, 30)},
+		{s: "0 hours ago", expectDate: date(2021, 3, 31)},
+		{s: "2 days ago", expectDate: date(2021, 3, 29)},
+		{s: "a week ago", expectDate: date(2021, 3, 24)},
+		{s: "3 weeks ago", expectDate: date(2021, 3, 10)},
+		{s: "2 months ago", expectDate: date(2021, 1, 31)},
+		{s: "2 years ago", expectDate: date(2019, 3, 31)},
+		{s: "Feb 3, 2000", expectDate: date(2000, 2, 3)},
+		{s: "Feb 3, 2000", location: newYork, expectDate: time.Date(2000, 2, 3, 0, 0, 0, 0, newYork)},
+		{s: "3 fortnights ago", expectErrContains: "unknown unit 'fortnights' when parsing '3 fortnights ago'"},
+		{s: "some hours ago", expectErrContains: "parsing quantity 'some' of time 'some hours ago'"},
+		{s: "long ago", expectErrContains: "expected '<quantity> <unit> ago'"},
+		{s: "February 333, 20", expectErrContains: "parsing date 'February 333, 20'"},
+	}
+	for _, c := range cases {
+		name := c.s
+		if c.location != nil {
+			name += " in " + c.location.String()
+		}
+		t.Run(name, func(t *testing.T) {
+			options := []Option{WithClock(func() time.Time { return now })}
+			if c.location != nil {
+				options = append(options, WithTimezone(c.location))
+			}
+			client := New(options...).(*client)
+			d, err := client.parseDate(c.s)
+			if c.expectErrContains != "" {
+				assert.Contains(t, err.Error(), c.expectErrContains)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expectDate, d)
+		})
+	}
+}
+
 func TestClient_DescribePackage(t *testing.T) {
 	cases := []struct {
 		name              string
 		html              string
 		httpCode          int
+		allowModule       bool
 		expectErrContains string
 		expectPackage     Package
 	}{
@@ -118,10 +231,17 @@ func TestClient_DescribePackage(t *testing.T) {
 <div data-test-id="UnitHeader-version"><div>  fooversion  </div>
 <div data-test-id="UnitHeader-licenses"><div>  foolicense  </div>
 <div class="UnitMeta"><ul>
-  <li><img alt="checked"/></li>
-  <li><img alt="checked"/></li>
-  <li><img alt="checked"/></li>
-  <li><img alt="checked"/></li>
+  <li><img alt="checked"/>
+    <details class="go-Tooltip"><summary>Module file</summary>
+      <p>The Go module system was introduced in Go 1.11. <a href="https://go.dev/ref/mod#go-mod-file">Learn more.</a></p>
+    </details>
+  </li>
+  <li><img alt="checked"/>Redistributable license
+    <details class="go-Tooltip"><summary></summary><p>Redistributable licenses place minimal restrictions. <a href="/license-policy">Learn more.</a></p></details>
+  </li>
+  <li><img alt="checked"/><details><summary>Tagged version</summary><p>Modules with tagged versions are stable. <a href="https://go.dev/doc/modules/version-numbers">Learn more.</a></p></details></li>
+  <li><img alt="checked"/><details><summary>Semantic version</summary><p>Versions follow semver. <a href="https://go.dev/doc/modules/version-numbers">Learn more.</a></p></details></li>
+  <li><img alt="unchecked"/>Reproducible build</li>
 </ul></div>
 <div class="UnitMeta-repo"><div>
     foorepo    </div>
@@ -137,11 +257,55 @@ func TestClient_DescribePackage(t *testing.T) {
 				HasValidGoModFile:         true,
 				HasRedistributableLicense: true,
 				HasTaggedVersion:          true,
-				HasStableVersion:          true,
-				Repository:                "foorepo",
-				Published:                 "2000-02-03",
-				IsModule:                  true,
-				IsPackage:                 true,
+				// the relabeled check has the link of both the tagged and stable version checks, so it is unknown
+				HasStableVersion: false,
+				Checks: map[string]bool{
+					"Module file":             true,
+					"Redistributable license": true,
+					"Tagged version":          true,
+					"Semantic version":        true,
+					"Reproducible build":      false,
+				},
+				CheckDetails: map[string]CheckDetail{
+					"Module file":             {Tooltip: "The Go module system was introduced in Go 1.11. Learn more.", URL: "https://go.dev/ref/mod#go-mod-file"},
+					"Redistributable license": {Tooltip: "Redistributable licenses place minimal restrictions. Learn more.", URL: "http://{addr}/license-policy"},
+					"Tagged version":          {Tooltip: "Modules with tagged versions are stable. Learn more.", URL: "https://go.dev/doc/modules/version-numbers"},
+					"Semantic version":        {Tooltip: "Versions follow semver. Learn more.", URL: "https://go.dev/doc/modules/version-numbers"},
+					"Reproducible build":      {},
+				},
+				Repository:    "foorepo",
+				Published:     "2000-02-03",
+				PublishedTime: date(2000, 2, 3),
+				IsModule:      true,
+				IsPackage:     true,
+			},
+		},
+		{
+			name: "banners",
+			html: `
+<html>
+<div class="UnitHeader-banner UnitHeader-banner--deprecated">
+  <img height="24px" width="24px" alt="">
+  <span class="UnitHeader-bannerContent">Deprecated: use example.com/new instead.</span>
+</div>
+<div class="UnitHeader-banner UnitHeader-banner--retracted">
+  <span class="UnitHeader-bannerContent">This version has been retracted: contains a security bug</span>
+</div>
+<div class="UnitHeader-banner UnitHeader-banner--majorVersion">
+  <span class="UnitHeader-bannerContent">The highest tagged major version is <a href="/example.com/mod/v3">v3</a>.</span>
+</div>
+<div class="UnitHeader-titleHeading">Heading</div>
+<div>package</div>
+<div>something else</div>
+</html>`,
+			expectPackage: Package{
+				Package:            "somepackage",
+				IsPackage:          true,
+				Deprecated:         true,
+				DeprecationMessage: "use example.com/new instead.",
+				Retracted:          true,
+				RetractionReason:   "contains a security bug",
+				LatestMajorVersion: "example.com/mod/v3",
 			},
 		},
 		{
@@ -154,6 +318,12 @@ func TestClient_DescribePackage(t *testing.T) {
 			html:              `<div class="UnitHeader-titleHeading">Heading</div><div>module</div><div>something else</div>`,
 			expectErrContains: "IsPackage=false after parsing page for 'somepackage', this probably indicates a parsing bug",
 		},
+		{
+			name:          "module but not package if allowed",
+			html:          `<div class="UnitHeader-titleHeading">Heading</div><div>module</div><div>something else</div>`,
+			allowModule:   true,
+			expectPackage: Package{Package: "somepackage", IsModule: true},
+		},
 		{
 			name:              "returns an error if HTTP req fails",
 			httpCode:          500,
@@ -182,6 +352,422 @@ func TestClient_DescribePackage(t *testing.T) {
 			}, func(addr string) {
 				client := New(WithBaseURL("http://" + addr))
 				pkg, err := client.DescribePackage(DescribePackageRequest{
+					Package:     "somepackage",
+					AllowModule: c.allowModule,
+				})
+				if c.expectErrContains != "" {
+					assert.Contains(t, err.Error(), c.expectErrContains)
+					return
+				}
+				assert.NoError(t, err)
+				expected := c.expectPackage
+				if expected.CheckDetails != nil {
+					// relative links are resolved against the test server
+					expected.CheckDetails = map[string]CheckDetail{}
+					for label, details := range c.expectPackage.CheckDetails {
+						details.URL = strings.ReplaceAll(details.URL, "{addr}", addr)
+						expected.CheckDetails[label] = details
+					}
+				}
+				assert.Equal(t, expected, *pkg)
+			})
+		})
+	}
+}
+
+func TestFindKnownCheck(t *testing.T) {
+	cases := []struct {
+		name        string
+		check       check
+		expectLabel string
+		expectFound bool
+	}{
+		{
+			name:        "by label",
+			check:       check{label: "tagged VERSION"},
+			expectLabel: "Tagged version",
+			expectFound: true,
+		},
+		{
+			name:        "by the link of one check",
+			check:       check{label: "Module file", details: CheckDetail{URL: "https://go.dev/ref/mod#go-mod-file"}},
+			expectLabel: "Valid go.mod file",
+			expectFound: true,
+		},
+		{
+			name:        "by a resolved relative link",
+			check:       check{label: "License", details: CheckDetail{URL: "https://pkg.go.dev/license-policy"}},
+			expectLabel: "Redistributable license",
+			expectFound: true,
+		},
+		{
+			name:  "not by the link of several checks",
+			check: check{label: "Semantic version", details: CheckDetail{URL: "https://go.dev/doc/modules/version-numbers"}},
+		},
+		{
+			name:  "unknown label without a link",
+			check: check{label: "Reproducible build"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			known, found := findKnownCheck(c.check)
+			assert.Equal(t, c.expectFound, found)
+			assert.Equal(t, c.expectLabel, known.label)
+		})
+	}
+}
+
+func TestClient_DescribePackageGoVersion(t *testing.T) {
+	cases := []struct {
+		name          string
+		pkg           string
+		version       string
+		goMods        map[string]string
+		expectWarning string
+		// expectNoProxyReqs is true if the module proxy must not be requested
+		expectNoProxyReqs bool
+		expectGoVersion   string
+	}{
+		{
+			name: "package in a module",
+			pkg:  "github.com/Foo/mod/sub/pkg",
+			goMods: map[string]string{
+				"/proxy/github.com/!foo/mod/@v/v1.2.3.mod": "module github.com/Foo/mod\n\ngo 1.16 // comment\n\nrequire example.com/other v1.0.0\n",
+			},
+			expectGoVersion: "1.16",
+		},
+		{
+			name: "package with a version",
+			pkg:  "github.com/Foo/mod/sub/pkg@v1.2.3",
+			goMods: map[string]string{
+				"/proxy/github.com/!foo/mod/@v/v1.2.3.mod": "module github.com/Foo/mod\n\ngo 1.16\n",
+			},
+			expectGoVersion: "1.16",
+		},
+		{
+			name: "module without a go directive",
+			pkg:  "github.com/Foo/mod",
+			goMods: map[string]string{
+				"/proxy/github.com/!foo/mod/@v/v1.2.3.mod": "module github.com/Foo/mod\n",
+			},
+			expectGoVersion: "",
+		},
+		{
+			name: "warns if the go.mod file is invalid",
+			pkg:  "github.com/Foo/mod",
+			goMods: map[string]string{
+				"/proxy/github.com/!foo/mod/@v/v1.2.3.mod": "module github.com/Foo/mod\n\ngo 1.16 extra\n",
+			},
+			expectWarning:   "resolving go version of 'github.com/Foo/mod': parsing go.mod: go.mod:3:",
+			expectGoVersion: "",
+		},
+		{
+			name:              "standard library",
+			pkg:               "fmt",
+			version:           "go1.17.2",
+			expectNoProxyReqs: true,
+			expectGoVersion:   "",
+		},
+		{
+			name:            "warns if no module provides the package",
+			pkg:             "github.com/Foo/mod",
+			expectWarning:   "resolving go version of 'github.com/Foo/mod': unable to find the go.mod file of 'github.com/Foo/mod' at v1.2.3",
+			expectGoVersion: "",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			version := c.version
+			if version == "" {
+				version = "v1.2.3"
+			}
+			proxyReqs := 0
+			withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+				if strings.HasPrefix(r.URL.Path, "/proxy/") {
+					proxyReqs++
+					goMod, ok := c.goMods[r.URL.Path]
+					if !ok {
+						rw.WriteHeader(http.StatusGone)
+						return
+					}
+					rw.Write([]byte(goMod))
+					return
+				}
+				rw.Write([]byte(`<html><div data-test-id="UnitHeader-version"><div>Version: ` + version + `</div></div></html>`))
+			}, func(addr string) {
+				var warnings []string
+				client := New(
+					WithBaseURL("http://"+addr),
+					WithProxyURL("http://"+addr+"/proxy"),
+					WithWarningHandler(func(warning error) { warnings = append(warnings, warning.Error()) }),
+				)
+				pkg, err := client.DescribePackage(DescribePackageRequest{
+					Package:          c.pkg,
+					ResolveGoVersion: true,
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, c.expectGoVersion, pkg.GoVersion)
+				if c.expectWarning != "" {
+					assert.Len(t, warnings, 1)
+					assert.Contains(t, warnings[0], c.expectWarning)
+				} else {
+					assert.Empty(t, warnings)
+				}
+				if c.expectNoProxyReqs {
+					assert.Equal(t, 0, proxyReqs)
+				}
+			})
+		})
+	}
+}
+
+func TestClient_Imports(t *testing.T) {
+	cases := []struct {
+		name              string
+		html              string
+		httpCode          int
+		expectErrContains string
+		expectImports     Imports
+	}{
+		{
+			name: "happy case",
+			html: `
+<html><body><div class="Imports">
+<h2>Standard library imports</h2>
+<ul class="Imports-list">
+  <li><a href="/fmt">fmt</a></li>
+  <li><a href="/strings">strings</a></li>
+</ul>
+<h2>Imports</h2>
+<h3 class="Imports-heading">github.com/foo/bar</h3>
+<ul class="Imports-list">
+  <li><a href="/github.com/foo/bar">github.com/foo/bar</a></li>
+  <li><a href="/github.com/foo/bar/baz">github.com/foo/bar/baz</a></li>
+</ul>
+<h3 class="Imports-heading">github.com/foo/qux</h3>
+<ul class="Imports-list">
+  <li><a href="/github.com/foo/qux">github.com/foo/qux</a></li>
+</ul>
+</div></body></html>`,
+			expectImports: Imports{
+				Package: "somepackage",
+				Imports: []string{"github.com/foo/bar", "github.com/foo/bar/baz", "github.com/foo/qux"},
+				ModuleImports: map[string][]string{
+					"github.com/foo/bar": {"github.com/foo/bar", "github.com/foo/bar/baz"},
+					"github.com/foo/qux": {"github.com/foo/qux"},
+				},
+				StandardLibraryImports: []string{"fmt", "strings"},
+			},
+		},
+		{
+			name:          "no imports",
+			html:          `<div class="Imports"></div>`,
+			expectImports: Imports{Package: "somepackage", ModuleImports: map[string][]string{}},
+		},
+		{
+			name: "returns an error if an import has no module",
+			html: `
+<div class="Imports">
+<h2>Imports</h2>
+<ul class="Imports-list"><li>github.com/foo/bar</li></ul>
+</div>`,
+			expectErrContains: "found import 'github.com/foo/bar' of 'somepackage' without a module heading",
+		},
+		{
+			name:              "returns an error if HTTP req fails",
+			httpCode:          500,
+			expectErrContains: "Internal Server Error",
+		},
+		{
+			name:              "returns error on 404",
+			httpCode:          404,
+			expectErrContains: "not found on pkg.go.dev",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+				if c.httpCode != 0 {
+					rw.WriteHeader(c.httpCode)
+					return
+				}
+				rw.Write([]byte(c.html))
+			}, func(addr string) {
+				client := New(WithBaseURL("http://" + addr))
+				imports, err := client.Imports(ImportsRequest{
+					Package: "somepackage",
+				})
+				if c.expectErrContains != "" {
+					assert.Contains(t, err.Error(), c.expectErrContains)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, c.expectImports, *imports)
+			})
+		})
+	}
+}
+
+func TestClient_Licenses(t *testing.T) {
+	cases := []struct {
+		name              string
+		html              string
+		httpCode          int
+		expectErrContains string
+		expectLicenses    []License
+	}{
+		{
+			name: "happy case",
+			html: `
+<html><body>
+<section class="License">
+  <h2><div id="lic-0">MIT</div></h2>
+  <p>This is not legal advice. <a href="/license-policy">Read disclaimer.</a></p>
+  <pre class="License-contents">Permission is hereby granted...
+</pre>
+  <div class="License-source">Source: LICENSE-MIT</div>
+</section>
+<section class="License">
+  <h2><div id="lic-1">Apache-2.0</div></h2>
+  <pre class="License-contents">Apache License</pre>
+  <div class="License-source">Source: sub/LICENSE</div>
+</section>
+</body></html>`,
+			expectLicenses: []License{
+				{Name: "MIT", Source: "LICENSE-MIT", FullText: "Permission is hereby granted...\n"},
+				{Name: "Apache-2.0", Source: "sub/LICENSE", FullText: "Apache License"},
+			},
+		},
+		{
+			name:           "no licenses",
+			html:           "",
+			expectLicenses: nil,
+		},
+		{
+			name:              "returns an error if a license has no name",
+			html:              `<html><section class="License"><h2></h2><pre class="License-contents">text</pre></section></html>`,
+			expectErrContains: "found license without a name for 'somepackage'",
+		},
+		{
+			name:              "returns an error if HTTP req fails",
+			httpCode:          500,
+			expectErrContains: "Internal Server Error",
+		},
+		{
+			name:              "returns error on 404",
+			httpCode:          404,
+			expectErrContains: "not found on pkg.go.dev",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+				if c.httpCode != 0 {
+					rw.WriteHeader(c.httpCode)
+					return
+				}
+				rw.Write([]byte(c.html))
+			}, func(addr string) {
+				client := New(WithBaseURL("http://" + addr))
+				licenses, err := client.Licenses(LicensesRequest{
+					Package: "somepackage",
+				})
+				if c.expectErrContains != "" {
+					assert.Contains(t, err.Error(), c.expectErrContains)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, c.expectLicenses, licenses)
+			})
+		})
+	}
+}
+
+func TestClient_Versions(t *testing.T) {
+	cases := []struct {
+		name              string
+		html              string
+		httpCode          int
+		expectErrContains string
+		expectVersions    []Version
+	}{
+		{
+			name: "happy case",
+			html: `
+<html><body><div class="Versions-list">
+<div class="Version-major">v1</div>
+<div class="Version-tag"><a class="js-versionLink">v1.1.0</a></div>
+<details class="Version-details">
+  <summary class="Version-summary">Feb 3, 2000</summary>
+  <div class="Versions-symbols">
+    <div class="Versions-symbol"><a href="/somepackage@v1.1.0#Foo">func Foo() error</a></div>
+    <div class="Versions-symbol"><a href="/somepackage@v1.1.0#Bar.Baz">func (b *Bar) Baz()</a></div>
+  </div>
+</details>
+<div class="Version-major"></div>
+<div class="Version-tag"><a class="js-versionLink">v1.0.0</a></div>
+<div class="Version-commitTime">Jan 2, 2000</div>
+</div></body></html>`,
+			expectVersions: []Version{
+				{
+					MajorVersion: "v1",
+					FullVersion:  "v1.1.0",
+					SemVer:       &SemVer{Major: 1, Minor: 1},
+					Date:         "2000-02-03",
+					DateTime:     date(2000, 2, 3),
+					Changes: []Change{
+						{URL: "/somepackage@v1.1.0#Foo", Symbol: "Foo", SymbolSynopsis: "func Foo() error"},
+						{URL: "/somepackage@v1.1.0#Bar.Baz", Symbol: "Bar.Baz", SymbolSynopsis: "func (b *Bar) Baz()"},
+					},
+				},
+				{
+					MajorVersion: "v1",
+					FullVersion:  "v1.0.0",
+					SemVer:       &SemVer{Major: 1},
+					Date:         "2000-01-02",
+					DateTime:     date(2000, 1, 2),
+				},
+			},
+		},
+		{
+			name: "returns an error if a change has no symbol",
+			html: `
+<html><div class="Versions-list">
+<div class="Version-tag"><a class="js-versionLink">v1.1.0</a></div>
+<details class="Version-details">
+  <summary class="Version-summary">Feb 3, 2000</summary>
+  <div class="Versions-symbol"><a href="/somepackage@v1.1.0">func Foo() error</a></div>
+</details>
+</div></html>`,
+			expectErrContains: "unable to find symbol name in link '/somepackage@v1.1.0' for version 'v1.1.0'",
+		},
+		{
+			name:              "returns an error if HTTP req fails",
+			httpCode:          500,
+			expectErrContains: "Internal Server Error",
+		},
+		{
+			name:              "returns error on 404",
+			httpCode:          404,
+			expectErrContains: "not found on pkg.go.dev",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+				if c.httpCode != 0 {
+					rw.WriteHeader(c.httpCode)
+					return
+				}
+				rw.Write([]byte(c.html))
+			}, func(addr string) {
+				client := New(WithBaseURL("http://" + addr))
+				versions, err := client.Versions(VersionsRequest{
 					Package: "somepackage",
 				})
 				if c.expectErrContains != "" {
@@ -189,7 +775,61 @@ func TestClient_DescribePackage(t *testing.T) {
 					return
 				}
 				assert.NoError(t, err)
-				assert.Equal(t, c.expectPackage, *pkg)
+				for i := range c.expectVersions {
+					for j := range c.expectVersions[i].Changes {
+						c.expectVersions[i].Changes[j].URL = "http://" + addr + c.expectVersions[i].Changes[j].URL
+					}
+				}
+				assert.Equal(t, "somepackage", versions.Package)
+				assert.Equal(t, c.expectVersions, versions.Versions)
+			})
+		})
+	}
+}
+
+func TestClient_ContextCancellation(t *testing.T) {
+	methods := []struct {
+		name string
+		call func(ctx context.Context, c Client) error
+	}{
+		{name: "ImportedBy", call: func(ctx context.Context, c Client) error {
+			_, err := c.ImportedByContext(ctx, ImportedByRequest{Package: "somepackage"})
+			return err
+		}},
+		{name: "DescribePackage", call: func(ctx context.Context, c Client) error {
+			_, err := c.DescribePackageContext(ctx, DescribePackageRequest{Package: "somepackage"})
+			return err
+		}},
+		{name: "Versions", call: func(ctx context.Context, c Client) error {
+			_, err := c.VersionsContext(ctx, VersionsRequest{Package: "somepackage"})
+			return err
+		}},
+		{name: "Search", call: func(ctx context.Context, c Client) error {
+			_, err := c.SearchContext(ctx, SearchRequest{Query: "somequery", Limit: 10})
+			return err
+		}},
+		{name: "Imports", call: func(ctx context.Context, c Client) error {
+			_, err := c.ImportsContext(ctx, ImportsRequest{Package: "somepackage"})
+			return err
+		}},
+		{name: "Licenses", call: func(ctx context.Context, c Client) error {
+			_, err := c.LicensesContext(ctx, LicensesRequest{Package: "somepackage"})
+			return err
+		}},
+	}
+	for _, m := range methods {
+		t.Run(m.name, func(t *testing.T) {
+			// the handler hangs until the client gives up on the request
+			withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+			}, func(addr string) {
+				client := New(WithBaseURL("http://" + addr))
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+
+				err := m.call(ctx, client)
+				assert.ErrorIs(t, err, ErrCanceled)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
 			})
 		})
 	}
@@ -1,52 +1,254 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/gosuri/uitable"
 	pkggodevclient "github.com/guseggert/pkggodev-client"
 	"github.com/logrusorgru/aurora/v3"
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
+	"golang.org/x/tools/imports"
 )
 
-var rootCmd = cobra.Command{
-	Use:   "pkggodev",
-	Short: "CLI interface for pkg.go.dev",
-}
-
 var stdoutIsTerminal = isatty.IsTerminal(os.Stdout.Fd())
 
-func init() {
+var (
+	cacheDir     string
+	noCache      bool
+	refreshCache bool
+	parseMode    string
+)
+
+// newRootCmd returns the command with all the subcommands.
+func newRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:   "pkggodev",
+		Short: "CLI interface for pkg.go.dev",
+	}
 	var format string
 	rootCmd.PersistentFlags().StringVarP(&format, "format", "f", "pretty", "pretty|json")
+	rootCmd.PersistentFlags().StringVar(&cacheDir, "cache-dir", defaultCacheDir(), "directory to cache pkg.go.dev responses in")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "don't cache pkg.go.dev responses")
+	rootCmd.PersistentFlags().BoolVar(&refreshCache, "refresh", false, "ignore cached responses for the given package(s) or query, and cache fresh ones")
+	rootCmd.PersistentFlags().StringVar(&parseMode, "parse-mode", "default", "default|strict|lenient, where strict fails and lenient warns if pkg.go.dev's markup changed")
 
-	rootCmd.AddCommand(&cobra.Command{
-		Use:           "imported-by package [packages...]",
-		Short:         "show the packages that import the given package(s)",
-		Args:          cobra.MinimumNArgs(1),
+	var importedByFromFile string
+	var importedByConcurrency int
+	importedByCmd := &cobra.Command{
+		Use:   "imported-by package [packages...]",
+		Short: "show the packages that import the given package(s), reading them from stdin if a package is '-'",
+		Long: "The output for a single package is the list of its results, and the output for several packages\n" +
+			"is grouped by package, as a list of objects with the package and its results in JSON.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pkgs, err := packageArgs(args, importedByFromFile)
+			if err != nil {
+				return err
+			}
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+			reqs := make([]pkggodevclient.ImportedByRequest, len(pkgs))
+			for i, pkg := range pkgs {
+				reqs[i] = pkggodevclient.ImportedByRequest{Package: pkg}
+			}
+			stream := &outputStream{format: format, ungrouped: len(pkgs) == 1}
+			defer stream.close()
+			results := &orderedResults{}
+			failed := 0
+			for result := range client.ImportedByBatch(commandContext(cmd), reqs, importedByConcurrency) {
+				result := result
+				err := results.add(result.Index, func() error {
+					group := importedByGroup{Package: result.Request.Package}
+					if result.Err != nil {
+						if stream.ungrouped {
+							return result.Err
+						}
+						failed++
+						group.Error = result.Err.Error()
+						fmt.Fprintf(os.Stderr, "%s: %s\n", group.Package, result.Err)
+					} else {
+						group.ImportedBy = result.ImportedBy.ImportedBy
+						group.Total = result.ImportedBy.Total
+						group.Complete = result.ImportedBy.Complete
+						if !group.Complete {
+							fmt.Fprintf(os.Stderr, "%s: pkg.go.dev only lists %d of %d known importers\n", group.Package, len(group.ImportedBy), group.Total)
+						}
+					}
+					return stream.printGroup(group.Package, group, group.ImportedBy, result.Err)
+				})
+				if err != nil {
+					return err
+				}
+			}
+			if err := commandContext(cmd).Err(); err != nil {
+				return err
+			}
+			if err := stream.close(); err != nil {
+				return err
+			}
+			if failed != 0 {
+				return fmt.Errorf("failed to get importers of %d of %d packages", failed, len(pkgs))
+			}
+			return nil
+		},
+	}
+	importedByCmd.Flags().StringVar(&importedByFromFile, "from-file", "", "read packages from the given file, one per line, or from stdin if it is '-'")
+	importedByCmd.Flags().IntVar(&importedByConcurrency, "concurrency", 4, "the maximum number of packages to look up at once")
+	rootCmd.AddCommand(importedByCmd)
+
+	var importsIncludeStd bool
+	importsCmd := &cobra.Command{
+		Use:           "imports package",
+		Short:         "show the packages imported by the given package",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+			imports, err := client.ImportsContext(commandContext(cmd), pkggodevclient.ImportsRequest{
+				Package: args[0],
+			})
+			if err != nil {
+				return err
+			}
+			var importPaths []string
+			if importsIncludeStd {
+				importPaths = append(importPaths, imports.StandardLibraryImports...)
+			}
+			importPaths = append(importPaths, imports.Imports...)
+			return printOutput(format, importPaths)
+		},
+	}
+	importsCmd.Flags().BoolVar(&importsIncludeStd, "std", false, "include standard library imports")
+	rootCmd.AddCommand(importsCmd)
+
+	var licensesFull bool
+	licensesCmd := &cobra.Command{
+		Use:           "licenses package",
+		Short:         "show the licenses of the given package",
+		Args:          cobra.ExactArgs(1),
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client := pkggodevclient.New()
-			importedBy, err := client.ImportedBy(pkggodevclient.ImportedByRequest{
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+			licenses, err := client.LicensesContext(commandContext(cmd), pkggodevclient.LicensesRequest{
 				Package: args[0],
 			})
 			if err != nil {
 				return err
 			}
-			err = printOutput(format, importedBy.ImportedBy)
+			if licensesFull {
+				return printOutput(format, licenses)
+			}
+			var names []string
+			for _, l := range licenses {
+				names = append(names, l.Name)
+			}
+			return printOutput(format, names)
+		},
+	}
+	licensesCmd.Flags().BoolVar(&licensesFull, "full", false, "include the source file and full text of each license")
+	rootCmd.AddCommand(licensesCmd)
+
+	var packagesInternal bool
+	var packagesSynopsis bool
+	packagesCmd := &cobra.Command{
+		Use:           "packages module",
+		Short:         "show the packages in the given module or in the subdirectories of the given package",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
 			if err != nil {
 				return err
 			}
-			return nil
+			dirs, err := client.DirectoriesContext(commandContext(cmd), pkggodevclient.DirectoriesRequest{
+				Package: args[0],
+			})
+			if err != nil {
+				return err
+			}
+			var importable []pkggodevclient.Directory
+			for _, d := range dirs {
+				if packagesInternal || !d.Internal {
+					importable = append(importable, d)
+				}
+			}
+			if packagesSynopsis {
+				return printOutput(format, importable)
+			}
+			var paths []string
+			for _, d := range importable {
+				paths = append(paths, d.Path)
+			}
+			return printOutput(format, paths)
 		},
-	})
+	}
+	packagesCmd.Flags().BoolVar(&packagesInternal, "internal", false, "include internal packages")
+	packagesCmd.Flags().BoolVar(&packagesSynopsis, "synopsis", false, "include the synopsis of each package")
+	rootCmd.AddCommand(packagesCmd)
+
+	var readmeText bool
+	readmeCmd := &cobra.Command{
+		Use:           "readme package",
+		Short:         "show the README of the given package",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+			readmeFormat := pkggodevclient.ReadmeFormatMarkdown
+			if readmeText {
+				readmeFormat = pkggodevclient.ReadmeFormatText
+			}
+			readme, err := client.ReadmeContext(commandContext(cmd), pkggodevclient.ReadmeRequest{
+				Package: args[0],
+				Format:  readmeFormat,
+			})
+			if err != nil {
+				return err
+			}
+			if format == "pretty" {
+				os.Stdout.WriteString(readme.Content + "\n")
+				return nil
+			}
+			return printOutput(format, readme)
+		},
+	}
+	readmeCmd.Flags().BoolVar(&readmeText, "text", false, "convert the README to plain text instead of Markdown")
+	rootCmd.AddCommand(readmeCmd)
 
 	var searchLimit int
+	var searchResolveVersions bool
+	var searchSymbols bool
 	searchCmd := &cobra.Command{
 		Use:           "search query",
 		Short:         "search for packages",
@@ -55,64 +257,333 @@ func init() {
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			query := args[0]
-			client := pkggodevclient.New()
-			res, err := client.Search(pkggodevclient.SearchRequest{
-				Query: query,
-				Limit: searchLimit,
-			})
+			client, err := newClient()
 			if err != nil {
 				return err
 			}
-			return printOutput(format, res.Results)
+			mode := pkggodevclient.SearchModePackage
+			if searchSymbols {
+				mode = pkggodevclient.SearchModeSymbol
+			}
+			it := client.SearchIterator(commandContext(cmd), pkggodevclient.SearchRequest{
+				Query:           query,
+				Limit:           searchLimit,
+				Mode:            mode,
+				ResolveVersions: searchResolveVersions,
+			})
+			// print results as they arrive, since each page of results is a separate request
+			stream := &outputStream{format: format}
+			defer stream.close()
+			for it.Next() {
+				var v interface{} = it.Result()
+				if searchSymbols {
+					v = it.SymbolResult()
+				}
+				err := stream.print(v)
+				if err != nil {
+					return err
+				}
+			}
+			if err := it.Err(); err != nil {
+				return err
+			}
+			return stream.close()
 		},
 	}
-	searchCmd.Flags().IntVar(&searchLimit, "limit", 25, "")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 25, "maximum number of results, or 0 for no limit")
+	searchCmd.Flags().BoolVar(&searchSymbols, "symbols", false, "search for symbols instead of packages")
+	searchCmd.Flags().BoolVar(&searchResolveVersions, "resolve-versions", false, "resolve truncated pseudo-versions, which requires a request per pseudo-version")
 	rootCmd.AddCommand(searchCmd)
 
 	rootCmd.AddCommand(&cobra.Command{
-		Use:           "versions package [package]...",
-		Short:         "show version information for the given package(s)",
-		Args:          cobra.MinimumNArgs(1),
+		Use:           "doc package",
+		Short:         "show the API documentation of the given package",
+		Args:          cobra.ExactArgs(1),
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client := pkggodevclient.New()
-			versions, err := client.Versions(pkggodevclient.VersionsRequest{
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+			doc, err := client.DocumentationContext(commandContext(cmd), pkggodevclient.DocumentationRequest{
 				Package: args[0],
 			})
 			if err != nil {
 				return err
 			}
-			err = printOutput(format, versions.Versions)
+			if format == "pretty" {
+				// the documentation is too nested to print as a table
+				printDocumentation(doc)
+				return nil
+			}
+			return printOutput(format, doc)
+		},
+	})
+
+	var examplesOutDir string
+	examplesCmd := &cobra.Command{
+		Use:           "examples package",
+		Short:         "show the examples in the documentation of the given package",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+			examples, err := client.ExamplesContext(commandContext(cmd), pkggodevclient.ExamplesRequest{
+				Package: args[0],
+			})
 			if err != nil {
 				return err
 			}
+			if examplesOutDir != "" {
+				paths, err := writeExamples(examplesOutDir, args[0], examples)
+				if err != nil {
+					return err
+				}
+				return printOutput(format, paths)
+			}
+			if format == "pretty" {
+				for _, e := range examples {
+					src, err := exampleSource(args[0], e)
+					if err != nil {
+						return err
+					}
+					os.Stdout.Write(append(src, '\n'))
+				}
+				return nil
+			}
+			return printOutput(format, examples)
+		},
+	}
+	examplesCmd.Flags().StringVar(&examplesOutDir, "out-dir", "", "write each example to main.go in its own subdirectory of this directory")
+	rootCmd.AddCommand(examplesCmd)
+
+	var versionsFromFile string
+	var versionsConcurrency int
+	versionsCmd := &cobra.Command{
+		Use:   "versions package [package]...",
+		Short: "show version information for the given package(s), reading them from stdin if a package is '-'",
+		Long: "The output for a single package is the list of its results, and the output for several packages\n" +
+			"is grouped by package, as a list of objects with the package and its results in JSON.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pkgs, err := packageArgs(args, versionsFromFile)
+			if err != nil {
+				return err
+			}
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+			reqs := make([]pkggodevclient.VersionsRequest, len(pkgs))
+			for i, pkg := range pkgs {
+				reqs[i] = pkggodevclient.VersionsRequest{Package: pkg}
+			}
+			stream := &outputStream{format: format, ungrouped: len(pkgs) == 1}
+			defer stream.close()
+			results := &orderedResults{}
+			failed := 0
+			for result := range client.VersionsBatch(commandContext(cmd), reqs, versionsConcurrency) {
+				result := result
+				err := results.add(result.Index, func() error {
+					group := versionsGroup{Package: result.Request.Package}
+					if result.Err != nil {
+						if stream.ungrouped {
+							return result.Err
+						}
+						failed++
+						group.Error = result.Err.Error()
+						fmt.Fprintf(os.Stderr, "%s: %s\n", group.Package, result.Err)
+					} else {
+						group.Versions = result.Versions.Versions
+					}
+					return stream.printGroup(group.Package, group, group.Versions, result.Err)
+				})
+				if err != nil {
+					return err
+				}
+			}
+			if err := commandContext(cmd).Err(); err != nil {
+				return err
+			}
+			if err := stream.close(); err != nil {
+				return err
+			}
+			if failed != 0 {
+				return fmt.Errorf("failed to get versions of %d of %d packages", failed, len(pkgs))
+			}
 			return nil
 		},
-	})
-	rootCmd.AddCommand(&cobra.Command{
+	}
+	versionsCmd.Flags().StringVar(&versionsFromFile, "from-file", "", "read packages from the given file, one per line, or from stdin if it is '-'")
+	versionsCmd.Flags().IntVar(&versionsConcurrency, "concurrency", 4, "the maximum number of packages to look up at once")
+	rootCmd.AddCommand(versionsCmd)
+
+	var auditGoSum string
+	var auditSkipIndirect bool
+	var auditConcurrency int
+	auditCmd := &cobra.Command{
+		Use:           "audit [go.mod]",
+		Short:         "audit the requirements of a go.mod file, which defaults to the one in the current directory",
+		Args:          cobra.MaximumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			goModPath := "go.mod"
+			if len(args) == 1 {
+				goModPath = args[0]
+			}
+			goMod, err := os.ReadFile(goModPath)
+			if err != nil {
+				return fmt.Errorf("reading go.mod: %w", err)
+			}
+			req := pkggodevclient.AuditRequest{
+				GoMod:        goMod,
+				SkipIndirect: auditSkipIndirect,
+				Concurrency:  auditConcurrency,
+			}
+			goSumPath := auditGoSum
+			if goSumPath == "" {
+				goSumPath = filepath.Join(filepath.Dir(goModPath), "go.sum")
+			}
+			req.GoSum, err = os.ReadFile(goSumPath)
+			if err != nil && (auditGoSum != "" || !errors.Is(err, os.ErrNotExist)) {
+				return fmt.Errorf("reading go.sum: %w", err)
+			}
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+			report, err := client.AuditContext(commandContext(cmd), req)
+			if err != nil {
+				return err
+			}
+			failed := 0
+			flagged := 0
+			for _, m := range report.Modules {
+				if m.Err != nil {
+					failed++
+					fmt.Fprintf(os.Stderr, "%s: %s\n", moduleAuditName(m), m.Err)
+				}
+				if len(m.Findings) != 0 {
+					flagged++
+				}
+			}
+			if format == "pretty" {
+				printAuditReport(report)
+			} else if err := printOutput(format, report); err != nil {
+				return err
+			}
+			if failed != 0 {
+				return fmt.Errorf("failed to audit %d of %d modules", failed, len(report.Modules))
+			}
+			if flagged != 0 {
+				return fmt.Errorf("found problems with %d of %d modules", flagged, len(report.Modules))
+			}
+			return nil
+		},
+	}
+	auditCmd.Flags().StringVar(&auditGoSum, "go-sum", "", "the go.sum file to check for missing checksums, which defaults to the one next to the go.mod file, if any")
+	auditCmd.Flags().BoolVar(&auditSkipIndirect, "skip-indirect", false, "skip requirements marked '// indirect'")
+	auditCmd.Flags().IntVar(&auditConcurrency, "concurrency", 4, "the maximum number of modules to look up at once")
+	rootCmd.AddCommand(auditCmd)
+
+	var packageInfoGoVersion bool
+	var packageInfoConcurrency int
+	packageInfoCmd := &cobra.Command{
 		Use:           "package-info package [package]...",
 		Short:         "show package information for the given package(s)",
 		Args:          cobra.MinimumNArgs(1),
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client := pkggodevclient.New()
-			for _, pkg := range args {
-				d, err := client.DescribePackage(pkggodevclient.DescribePackageRequest{
-					Package: pkg,
-				})
-				if err != nil {
-					return err
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+			reqs := make([]pkggodevclient.DescribePackageRequest, len(args))
+			for i, pkg := range args {
+				reqs[i] = pkggodevclient.DescribePackageRequest{
+					Package:          pkg,
+					ResolveGoVersion: packageInfoGoVersion,
 				}
-				err = printOutput(format, d)
+			}
+			results := &orderedResults{}
+			failed := 0
+			for result := range client.DescribePackageBatch(commandContext(cmd), reqs, packageInfoConcurrency) {
+				result := result
+				err := results.add(result.Index, func() error {
+					if result.Err != nil {
+						failed++
+						fmt.Fprintf(os.Stderr, "%s: %s\n", result.Request.Package, result.Err)
+						return nil
+					}
+					return printOutput(format, result.Package)
+				})
 				if err != nil {
 					return err
 				}
 			}
+			if err := commandContext(cmd).Err(); err != nil {
+				return err
+			}
+			if failed != 0 {
+				return fmt.Errorf("failed to describe %d of %d packages", failed, len(args))
+			}
 			return nil
 		},
-	})
+	}
+	packageInfoCmd.Flags().BoolVar(&packageInfoGoVersion, "go-version", false, "include the go version of the module's go.mod file, which requires requests to the module proxy")
+	packageInfoCmd.Flags().IntVar(&packageInfoConcurrency, "concurrency", 4, "the maximum number of packages to describe at once")
+	rootCmd.AddCommand(packageInfoCmd)
+	return rootCmd
+}
+
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pkggodev")
+}
+
+// newClient returns the client for commands, which tests replace.
+var newClient = newPkgGoDevClient
+
+func newPkgGoDevClient() (pkggodevclient.Client, error) {
+	options := []pkggodevclient.Option{
+		pkggodevclient.WithRetryPolicy(pkggodevclient.DefaultRetryPolicy()),
+		// besides the errors that lenient parsing ignores, this shows failed optional lookups in every mode
+		pkggodevclient.WithWarningHandler(func(warning error) {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		}),
+	}
+	switch mode := pkggodevclient.ParseMode(parseMode); mode {
+	case pkggodevclient.ParseModeDefault, pkggodevclient.ParseModeStrict, pkggodevclient.ParseModeLenient:
+		options = append(options, pkggodevclient.WithParseMode(mode))
+	default:
+		return nil, fmt.Errorf("unknown parse mode '%s'", parseMode)
+	}
+	if !noCache && cacheDir != "" {
+		cache, err := pkggodevclient.NewFileCache(cacheDir)
+		if err != nil {
+			return nil, fmt.Errorf("creating cache: %w", err)
+		}
+		options = append(options, pkggodevclient.WithCache(cache, time.Hour))
+	}
+	return pkggodevclient.New(options...), nil
+}
+
+func commandContext(cmd *cobra.Command) context.Context {
+	if refreshCache {
+		return pkggodevclient.RefreshCache(cmd.Context())
+	}
+	return cmd.Context()
 }
 
 func printStruct(v reflect.Value) {
@@ -173,8 +644,350 @@ func printOutput(format string, v interface{}) error {
 	return nil
 }
 
+// exampleSource returns the source of a main package that runs the example of pkg.
+// pkg.go.dev shows playable examples as complete programs, and other examples as the body of the example function,
+// so the imports of those are added like goimports does, including pkg if the example uses it.
+func exampleSource(pkg string, e pkggodevclient.Example) ([]byte, error) {
+	var b strings.Builder
+	if e.Doc != "" {
+		for _, line := range strings.Split(e.Doc, "\n") {
+			b.WriteString(strings.TrimSpace("// "+line) + "\n")
+		}
+	}
+	if strings.HasPrefix(e.Code, "package ") {
+		b.WriteString(e.Code + "\n")
+	} else {
+		b.WriteString("package main\n\n")
+		if name := packageName(pkg); name == path.Base(pkg) {
+			fmt.Fprintf(&b, "import %q\n\n", pkg)
+		} else {
+			fmt.Fprintf(&b, "import %s %q\n\n", name, pkg)
+		}
+		fmt.Fprintf(&b, "// %s\nfunc main() {\n", e.Name)
+		for _, line := range strings.Split(e.Code, "\n") {
+			if line != "" {
+				b.WriteString("\t" + line)
+			}
+			b.WriteString("\n")
+		}
+		if e.Output != "" {
+			b.WriteString("\n\t// Output:\n")
+			for _, line := range strings.Split(e.Output, "\n") {
+				b.WriteString("\t// " + line + "\n")
+			}
+		}
+		b.WriteString("}\n")
+	}
+	src, err := imports.Process(e.Name+".go", []byte(b.String()), nil)
+	if err != nil {
+		return nil, fmt.Errorf("fixing imports of example '%s': %w", e.Name, err)
+	}
+	return src, nil
+}
+
+// packageName returns the likely name of the package with the given import path, like goimports assumes it:
+// the last element of the path without a major version suffix, a "go-" prefix or a "-go" suffix,
+// up to its first character that isn't valid in an identifier.
+func packageName(pkg string) string {
+	name := path.Base(pkg)
+	if semverMajorRegexp.MatchString(name) && path.Dir(pkg) != "." {
+		name = path.Base(path.Dir(pkg))
+	}
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(strings.TrimSuffix(name, "-go"), ".go")
+	if i := strings.IndexFunc(name, func(r rune) bool {
+		return !(r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r))
+	}); i != -1 {
+		name = name[:i]
+	}
+	return name
+}
+
+var semverMajorRegexp = regexp.MustCompile(`^v[2-9][0-9]*$`)
+
+// writeExamples writes each example of pkg to main.go in its own subdirectory of dir, named after the example,
+// since each is a main package. It returns the paths of the files.
+func writeExamples(dir string, pkg string, examples []pkggodevclient.Example) ([]string, error) {
+	var paths []string
+	for _, e := range examples {
+		src, err := exampleSource(pkg, e)
+		if err != nil {
+			return nil, err
+		}
+		exampleDir := filepath.Join(dir, e.Name)
+		err = os.MkdirAll(exampleDir, 0755)
+		if err != nil {
+			return nil, fmt.Errorf("creating output directory: %w", err)
+		}
+		file := filepath.Join(exampleDir, "main.go")
+		err = os.WriteFile(file, src, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("writing example '%s': %w", e.Name, err)
+		}
+		paths = append(paths, file)
+	}
+	return paths, nil
+}
+
+// printDocumentation prints the declarations of a package in the style of "go doc".
+func printDocumentation(doc *pkggodevclient.Documentation) {
+	w := os.Stdout
+	fmt.Fprintf(w, "package %s\n\n", doc.Package)
+	if doc.Overview != "" {
+		fmt.Fprintf(w, "%s\n\n", doc.Overview)
+	}
+	for _, d := range doc.Constants {
+		fmt.Fprintf(w, "%s\n", d.Code)
+	}
+	for _, d := range doc.Variables {
+		fmt.Fprintf(w, "%s\n", d.Code)
+	}
+	for _, f := range doc.Functions {
+		fmt.Fprintf(w, "%s\n", f.Signature)
+	}
+	for _, t := range doc.Types {
+		// only print the first line of the type, since struct and interface declarations can be long
+		decl := t.Declaration
+		if i := strings.Index(decl, "\n"); i >= 0 {
+			decl = decl[:i]
+			if strings.HasSuffix(decl, "{") {
+				decl += " ... }"
+			}
+		}
+		fmt.Fprintf(w, "%s\n", decl)
+		for _, d := range t.Constants {
+			fmt.Fprintf(w, "    %s\n", strings.ReplaceAll(d.Code, "\n", "\n    "))
+		}
+		for _, d := range t.Variables {
+			fmt.Fprintf(w, "    %s\n", strings.ReplaceAll(d.Code, "\n", "\n    "))
+		}
+		for _, f := range t.Functions {
+			fmt.Fprintf(w, "    %s\n", f.Signature)
+		}
+		for _, f := range t.Methods {
+			fmt.Fprintf(w, "    %s\n", f.Signature)
+		}
+	}
+}
+
+// printAuditReport prints the findings of each module that has any.
+func printAuditReport(report *pkggodevclient.AuditReport) {
+	w := os.Stdout
+	flagged := 0
+	audited := 0
+	for _, m := range report.Modules {
+		if m.Err == nil {
+			audited++
+		}
+		if len(m.Findings) == 0 {
+			continue
+		}
+		if flagged > 0 {
+			fmt.Fprintln(w)
+		}
+		flagged++
+		header := moduleAuditName(m) + ":"
+		if stdoutIsTerminal {
+			fmt.Fprintln(w, aurora.Bold(header))
+		} else {
+			fmt.Fprintln(w, header)
+		}
+		for _, f := range m.Findings {
+			fmt.Fprintf(w, "    %s: %s\n", f.Kind, f.Message)
+		}
+	}
+	if flagged == 0 && audited != 0 {
+		fmt.Fprintf(w, "no problems found with %d audited modules\n", audited)
+	}
+}
+
+// moduleAuditName returns the required module of an audit and its replacement, if any, like "go mod edit" shows them.
+func moduleAuditName(m pkggodevclient.ModuleAudit) string {
+	name := m.Path + "@" + m.Version
+	if m.ReplacePath != "" {
+		name += " => " + m.ReplacePath
+		if m.ReplaceVersion != "" {
+			name += "@" + m.ReplaceVersion
+		}
+	}
+	return name
+}
+
+// packageArgs returns the packages named by args and the file fromFile, if any.
+// An arg of '-', or a fromFile of '-', reads packages from stdin.
+func packageArgs(args []string, fromFile string) ([]string, error) {
+	var pkgs []string
+	readStdin := fromFile == "-"
+	for _, arg := range args {
+		if arg == "-" {
+			readStdin = true
+			continue
+		}
+		pkgs = append(pkgs, arg)
+	}
+	if fromFile != "" && fromFile != "-" {
+		f, err := os.Open(fromFile)
+		if err != nil {
+			return nil, fmt.Errorf("opening package list: %w", err)
+		}
+		defer f.Close()
+		filePkgs, err := readPackageList(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading package list '%s': %w", fromFile, err)
+		}
+		pkgs = append(pkgs, filePkgs...)
+	}
+	if readStdin {
+		stdinPkgs, err := readPackageList(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("reading package list from stdin: %w", err)
+		}
+		pkgs = append(pkgs, stdinPkgs...)
+	}
+	if len(pkgs) == 0 {
+		return nil, errors.New("no packages given")
+	}
+	return pkgs, nil
+}
+
+// readPackageList reads packages from r, one per line, ignoring blank lines and lines starting with '#'.
+func readPackageList(r io.Reader) ([]string, error) {
+	var pkgs []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pkgs = append(pkgs, line)
+	}
+	return pkgs, scanner.Err()
+}
+
+// orderedResults calls the funcs added for batch results in the order of their indexes,
+// holding funcs back until those of all lower indexes have been called.
+type orderedResults struct {
+	next    int
+	pending map[int]func() error
+}
+
+func (o *orderedResults) add(index int, f func() error) error {
+	if o.pending == nil {
+		o.pending = map[int]func() error{}
+	}
+	o.pending[index] = f
+	for {
+		f, ok := o.pending[o.next]
+		if !ok {
+			return nil
+		}
+		delete(o.pending, o.next)
+		o.next++
+		if err := f(); err != nil {
+			return err
+		}
+	}
+}
+
+// versionsGroup is the output of the versions command for a package.
+type versionsGroup struct {
+	Package  string
+	Versions []pkggodevclient.Version
+	Error    string `json:",omitempty"`
+}
+
+// importedByGroup is the output of the imported-by command for a package.
+type importedByGroup struct {
+	Package    string
+	ImportedBy []string
+	Total      int
+	Complete   bool
+	Error      string `json:",omitempty"`
+}
+
+// outputStream prints the elements of a slice as they become available,
+// in the same format as printOutput prints the whole slice.
+type outputStream struct {
+	format string
+	// ungrouped prints the items of the only package like printOutput, without the package, which is how
+	// the output of commands for a single package looked before they accepted several
+	ungrouped bool
+	n         int
+	closed    bool
+}
+
+func (s *outputStream) print(v interface{}) error {
+	defer func() { s.n++ }()
+	switch s.format {
+	case "json":
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("formatting JSON output: %w", err)
+		}
+		if s.n == 0 {
+			os.Stdout.WriteString("[")
+		} else {
+			os.Stdout.WriteString(",")
+		}
+		os.Stdout.Write(b)
+	case "pretty":
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Struct {
+			printStruct(rv)
+		} else {
+			fmt.Fprintf(os.Stdout, "%v", v)
+		}
+		os.Stdout.WriteString("\n")
+	default:
+		return fmt.Errorf("unknown format type '%s'", s.format)
+	}
+	return nil
+}
+
+// printGroup prints the output for a package.
+// JSON output is the group as an element of the stream, and pretty output is the package followed by its items.
+// Pretty output skips packages that failed, since their errors are printed to stderr.
+func (s *outputStream) printGroup(pkg string, group interface{}, items interface{}, err error) error {
+	if s.ungrouped {
+		return printOutput(s.format, items)
+	}
+	if s.format != "pretty" {
+		return s.print(group)
+	}
+	if err != nil {
+		return nil
+	}
+	if s.n > 0 {
+		os.Stdout.WriteString("\n")
+	}
+	s.n++
+	if stdoutIsTerminal {
+		fmt.Fprintln(os.Stdout, aurora.Bold(pkg+":"))
+	} else {
+		fmt.Fprintln(os.Stdout, pkg+":")
+	}
+	return printOutput(s.format, items)
+}
+
+// close ends the output, and can be called more than once.
+func (s *outputStream) close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.format == "json" && !s.ungrouped {
+		if s.n == 0 {
+			os.Stdout.WriteString("[")
+		}
+		os.Stdout.WriteString("]\n")
+	}
+	return nil
+}
+
 func main() {
-	err := rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err := newRootCmd().ExecuteContext(ctx)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	pkggodevclient "github.com/guseggert/pkggodev-client"
+	"github.com/guseggert/pkggodev-client/pkggodevtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteExamples(t *testing.T) {
+	goCmd, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	examples := []pkggodevclient.Example{
+		{
+			Name: "Example",
+			Doc:  "This example is playable.",
+			Code: "package main\n\nimport (\n\t\"fmt\"\n\t\"strings\"\n)\n\nfunc main() {\n\tfmt.Println(strings.ToUpper(\"a\"))\n}",
+		},
+		{
+			Name:   "ExampleToUpper",
+			Symbol: "ToUpper",
+			Code:   "fmt.Println(strings.ToUpper(\"Gopher\"))",
+			Output: "GOPHER",
+		},
+		{
+			Name:   "ExampleBuilder",
+			Symbol: "Builder",
+			Code:   "var b strings.Builder\nb.WriteString(\"a\")\nfmt.Fprintln(os.Stdout, b.String())",
+		},
+		{
+			Name: "ExampleNoPackage",
+			Code: "fmt.Println(\"doesn't use the package\")",
+		},
+	}
+	dir := t.TempDir()
+
+	paths, err := writeExamples(dir, "strings", examples)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "Example", "main.go"),
+		filepath.Join(dir, "ExampleToUpper", "main.go"),
+		filepath.Join(dir, "ExampleBuilder", "main.go"),
+		filepath.Join(dir, "ExampleNoPackage", "main.go"),
+	}, paths)
+
+	src, err := os.ReadFile(paths[1])
+	require.NoError(t, err)
+	assert.Equal(t, `package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExampleToUpper
+func main() {
+	fmt.Println(strings.ToUpper("Gopher"))
+
+	// Output:
+	// GOPHER
+}
+`, string(src))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module examples\n\ngo 1.18\n"), 0644))
+	cmd := exec.Command(goCmd, "vet", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off")
+	out, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(out))
+}
+
+func TestPackageName(t *testing.T) {
+	cases := map[string]string{
+		"strings":                            "strings",
+		"github.com/foo/bar":                 "bar",
+		"github.com/foo/bar/v2":              "bar",
+		"github.com/foo/go-bar":              "bar",
+		"github.com/foo/bar-go":              "bar",
+		"gopkg.in/yaml.v3":                   "yaml",
+		"github.com/foo/bar.go":              "bar",
+		"github.com/foo/v1":                  "v1",
+		"github.com/ipfs/go-ipfs-blockstore": "ipfs",
+	}
+	for pkg, expected := range cases {
+		assert.Equal(t, expected, packageName(pkg), pkg)
+	}
+}
+
+// reversedBatchClient sends batch results in the reverse order of the requests.
+type reversedBatchClient struct {
+	*pkggodevtest.Client
+}
+
+func (c reversedBatchClient) DescribePackageBatch(ctx context.Context, reqs []pkggodevclient.DescribePackageRequest, concurrency int) <-chan pkggodevclient.DescribePackageResult {
+	var results []pkggodevclient.DescribePackageResult
+	for result := range c.Client.DescribePackageBatch(ctx, reqs, concurrency) {
+		results = append([]pkggodevclient.DescribePackageResult{result}, results...)
+	}
+	reversed := make(chan pkggodevclient.DescribePackageResult, len(results))
+	for _, result := range results {
+		reversed <- result
+	}
+	close(reversed)
+	return reversed
+}
+
+// runCommand runs the CLI with args and returns its stdout and stderr.
+func runCommand(t *testing.T, ctx context.Context, client pkggodevclient.Client, args ...string) (string, string, error) {
+	oldNewClient, oldStdout, oldStderr := newClient, os.Stdout, os.Stderr
+	defer func() {
+		newClient, os.Stdout, os.Stderr = oldNewClient, oldStdout, oldStderr
+	}()
+	newClient = func() (pkggodevclient.Client, error) { return client, nil }
+
+	stdoutR, stdoutW, err := os.Pipe()
+	require.NoError(t, err)
+	stderrR, stderrW, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout, os.Stderr = stdoutW, stderrW
+	var stdout, stderr []byte
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		stdout, _ = io.ReadAll(stdoutR)
+	}()
+	go func() {
+		defer wg.Done()
+		stderr, _ = io.ReadAll(stderrR)
+	}()
+
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs(args)
+	err = rootCmd.ExecuteContext(ctx)
+	stdoutW.Close()
+	stderrW.Close()
+	wg.Wait()
+	return string(stdout), string(stderr), err
+}
+
+func TestPackageInfo(t *testing.T) {
+	client := pkggodevtest.New().
+		SetPackage(pkggodevclient.Package{Package: "example.com/a", Version: "v1.0.0"}).
+		SetPackage(pkggodevclient.Package{Package: "example.com/c", Version: "v3.0.0"}).
+		SetError("example.com/b", errors.New("boom"))
+
+	stdout, stderr, err := runCommand(t, context.Background(), reversedBatchClient{client}, "package-info", "--format", "json", "example.com/a", "example.com/b", "example.com/c")
+
+	assert.EqualError(t, err, "failed to describe 1 of 3 packages")
+	assert.Equal(t, "example.com/b: boom\n", stderr)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if assert.Len(t, lines, 2) {
+		assert.Contains(t, lines[0], `"Package":"example.com/a"`)
+		assert.Contains(t, lines[1], `"Package":"example.com/c"`)
+	}
+}
+
+func TestVersions(t *testing.T) {
+	client := pkggodevtest.New().
+		SetVersions(pkggodevclient.Versions{Package: "example.com/a", Versions: []pkggodevclient.Version{{FullVersion: "v1.0.0"}}}).
+		SetError("example.com/b", errors.New("boom"))
+
+	t.Run("groups several packages", func(t *testing.T) {
+		stdout, _, err := runCommand(t, context.Background(), client, "versions", "--format", "json", "example.com/a", "example.com/b")
+		assert.EqualError(t, err, "failed to get versions of 1 of 2 packages")
+		var groups []versionsGroup
+		require.NoError(t, json.Unmarshal([]byte(stdout), &groups))
+		assert.Equal(t, []versionsGroup{
+			{Package: "example.com/a", Versions: []pkggodevclient.Version{{FullVersion: "v1.0.0"}}},
+			{Package: "example.com/b", Error: "boom"},
+		}, groups)
+	})
+	t.Run("prints a single package without grouping", func(t *testing.T) {
+		stdout, _, err := runCommand(t, context.Background(), client, "versions", "--format", "json", "example.com/a")
+		assert.NoError(t, err)
+		var versions []pkggodevclient.Version
+		require.NoError(t, json.Unmarshal([]byte(stdout), &versions))
+		assert.Equal(t, []pkggodevclient.Version{{FullVersion: "v1.0.0"}}, versions)
+	})
+	t.Run("returns the error of a single package", func(t *testing.T) {
+		stdout, _, err := runCommand(t, context.Background(), client, "versions", "--format", "json", "example.com/b")
+		assert.EqualError(t, err, "boom")
+		assert.Empty(t, stdout)
+	})
+	t.Run("closes the JSON output when canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		stdout, _, err := runCommand(t, ctx, client, "versions", "--format", "json", "example.com/a", "example.com/b")
+		assert.ErrorIs(t, err, context.Canceled)
+		var groups []versionsGroup
+		assert.NoError(t, json.Unmarshal([]byte(stdout), &groups))
+	})
+}
+
+func TestImportedBy(t *testing.T) {
+	client := pkggodevtest.New().
+		SetImportedBy(pkggodevclient.ImportedBy{Package: "example.com/a", ImportedBy: []string{"example.com/x"}, Total: 1, Complete: true}).
+		SetImportedBy(pkggodevclient.ImportedBy{Package: "example.com/b", ImportedBy: []string{"example.com/y"}, Total: 2})
+
+	stdout, stderr, err := runCommand(t, context.Background(), client, "imported-by", "--format", "json", "example.com/a", "example.com/b")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/b: pkg.go.dev only lists 1 of 2 known importers\n", stderr)
+	var groups []importedByGroup
+	require.NoError(t, json.Unmarshal([]byte(stdout), &groups))
+	assert.Equal(t, []importedByGroup{
+		{Package: "example.com/a", ImportedBy: []string{"example.com/x"}, Total: 1, Complete: true},
+		{Package: "example.com/b", ImportedBy: []string{"example.com/y"}, Total: 2},
+	}, groups)
+}
+
+func TestAudit(t *testing.T) {
+	goModPath := filepath.Join(t.TempDir(), "go.mod")
+	require.NoError(t, os.WriteFile(goModPath, []byte("module example.com/mod\n\nrequire example.com/a v1.0.0\n"), 0644))
+	client := pkggodevtest.New().SetAuditReport(pkggodevclient.AuditReport{
+		Module: "example.com/mod",
+		Modules: []pkggodevclient.ModuleAudit{
+			{Path: "example.com/a", Version: "v1.0.0", Err: errors.New("boom")},
+		},
+	})
+
+	stdout, stderr, err := runCommand(t, context.Background(), client, "audit", "--format", "json", goModPath)
+	assert.EqualError(t, err, "failed to audit 1 of 1 modules")
+	assert.Equal(t, "example.com/a@v1.0.0: boom\n", stderr)
+	var report struct {
+		Modules []struct {
+			Path  string
+			Error string
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &report))
+	if assert.Len(t, report.Modules, 1) {
+		assert.Equal(t, "example.com/a", report.Modules[0].Path)
+		assert.Equal(t, "boom", report.Modules[0].Error)
+	}
+}
+
+func TestReadPackageList(t *testing.T) {
+	pkgs, err := readPackageList(strings.NewReader("example.com/a\n\n  # a comment\n  example.com/b  \n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"example.com/a", "example.com/b"}, pkgs)
+}
@@ -0,0 +1,100 @@
+package pkggodevclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+)
+
+type DirectoriesRequest struct {
+	Package string
+}
+
+// Directory is a package in a subdirectory of a module or package.
+type Directory struct {
+	Path     string
+	Synopsis string
+	// Internal is true if the package is an internal package, which can only be imported by packages in the same tree
+	Internal bool
+}
+
+func (c *client) Directories(req DirectoriesRequest) ([]Directory, error) {
+	return c.DirectoriesContext(context.Background(), req)
+}
+
+func (c *client) DirectoriesContext(ctx context.Context, req DirectoriesRequest) ([]Directory, error) {
+	col := c.newCollector(ctx, EndpointPackage)
+	var dirs []Directory
+	errs := &ErrorList{}
+
+	col.OnHTML(".UnitDirectories-table tr", func(e *colly.HTMLElement) {
+		link := e.DOM.Find(".UnitDirectories-pathCell a").First()
+		if link.Length() == 0 {
+			// header rows and the rows that expand nested directories don't have a package link
+			return
+		}
+		href, _ := link.Attr("href")
+		path := directoryPath(href)
+		if path == "" {
+			errs.Errs = append(errs.Errs, &ParseError{
+				Package:  req.Package,
+				Selector: ".UnitDirectories-pathCell a",
+				Err:      fmt.Errorf("unable to find package path in link '%s' for '%s'", href, req.Package),
+			})
+			return
+		}
+		dirs = append(dirs, Directory{
+			Path:     path,
+			Synopsis: strings.TrimSpace(e.DOM.Find(".UnitDirectories-desktopSynopsis").Text()),
+			Internal: isInternal(path),
+		})
+	})
+
+	// packages without subdirectories have no directories section
+	c.expectElements(col, req.Package, errs,
+		expectedElement{selector: ".UnitHeader-titleHeading"},
+		expectedElement{selector: ".UnitDirectories-table", ifPresent: "#section-directories"},
+	)
+	col.OnError(func(r *colly.Response, e error) {
+		errs.Errs = append(errs.Errs, c.responseError(r, e))
+	})
+	if err := visit(col, fmt.Sprintf("%s/%s", c.baseURL, req.Package)); err != nil {
+		errs.Errs = append(errs.Errs, err)
+	}
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+	c.handleParseErrors(errs)
+	if len(errs.Errs) != 0 {
+		return nil, errs
+	}
+	return dirs, nil
+}
+
+// directoryPath returns the package path of a link to a package page, such as "/example.com/mod@v1.0.0/sub".
+func directoryPath(href string) string {
+	path := strings.TrimPrefix(href, "/")
+	if i := strings.IndexAny(path, "?#"); i != -1 {
+		path = path[:i]
+	}
+	// links to a specific version have the version after the module path
+	if at := strings.Index(path, "@"); at != -1 {
+		rest := path[at:]
+		path = path[:at]
+		if slash := strings.Index(rest, "/"); slash != -1 {
+			path += rest[slash:]
+		}
+	}
+	return path
+}
+
+func isInternal(path string) bool {
+	for _, elem := range strings.Split(path, "/") {
+		if elem == "internal" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package pkggodevclient
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Directories(t *testing.T) {
+	cases := []struct {
+		name              string
+		html              string
+		httpCode          int
+		expectErrContains string
+		expectDirs        []Directory
+	}{
+		{
+			name: "happy case",
+			html: `
+<html><body>
+<section class="UnitDirectories js-unitDirectories">
+  <h2 class="UnitDirectories-title" id="section-directories">Directories</h2>
+  <table class="UnitDirectories-table">
+    <tr class="UnitDirectories-tableHeader"><th>Path</th><th class="UnitDirectories-desktopSynopsis">Synopsis</th></tr>
+    <tr>
+      <td><div class="UnitDirectories-pathCell"><a href="/example.com/mod/foo">foo</a></div></td>
+      <td class="UnitDirectories-desktopSynopsis">Package foo does things.</td>
+    </tr>
+    <tr>
+      <td><div class="UnitDirectories-pathCell"><a href="/example.com/mod@v1.2.3/foo/bar">foo/bar</a></div></td>
+      <td class="UnitDirectories-desktopSynopsis"></td>
+    </tr>
+    <tr>
+      <td><div class="UnitDirectories-pathCell"><a href="/example.com/mod/internal/baz">internal/baz</a></div></td>
+      <td class="UnitDirectories-desktopSynopsis">Package baz is internal.</td>
+    </tr>
+  </table>
+</section>
+</body></html>`,
+			expectDirs: []Directory{
+				{Path: "example.com/mod/foo", Synopsis: "Package foo does things."},
+				{Path: "example.com/mod/foo/bar"},
+				{Path: "example.com/mod/internal/baz", Synopsis: "Package baz is internal.", Internal: true},
+			},
+		},
+		{
+			name:       "no directories",
+			html:       "<html></html>",
+			expectDirs: nil,
+		},
+		{
+			name:              "returns an error if a directory has no path",
+			html:              `<html><table class="UnitDirectories-table"><tr><td><div class="UnitDirectories-pathCell"><a href="/">x</a></div></td></tr></table></html>`,
+			expectErrContains: "unable to find package path in link '/' for 'somepackage'",
+		},
+		{
+			name:              "returns an error if HTTP req fails",
+			httpCode:          500,
+			expectErrContains: "Internal Server Error",
+		},
+		{
+			name:              "returns error on 404",
+			httpCode:          404,
+			expectErrContains: "not found on pkg.go.dev",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+				if c.httpCode != 0 {
+					rw.WriteHeader(c.httpCode)
+					return
+				}
+				rw.Write([]byte(c.html))
+			}, func(addr string) {
+				client := New(WithBaseURL("http://" + addr))
+				dirs, err := client.Directories(DirectoriesRequest{
+					Package: "somepackage",
+				})
+				if c.expectErrContains != "" {
+					assert.Contains(t, err.Error(), c.expectErrContains)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, c.expectDirs, dirs)
+			})
+		})
+	}
+}
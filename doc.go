@@ -0,0 +1,2 @@
+// Package pkggodevclient is a client for pkg.go.dev, which has no API, so the client scrapes its pages.
+package pkggodevclient
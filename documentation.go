@@ -0,0 +1,343 @@
+package pkggodevclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+)
+
+type DocumentationRequest struct {
+	Package string
+}
+
+// Documentation is the API documentation of a package.
+type Documentation struct {
+	Package   string
+	Overview  string
+	Constants []Declaration
+	Variables []Declaration
+	Functions []Function
+	Types     []Type
+}
+
+// Declaration is a group of constants or variables that are declared together.
+type Declaration struct {
+	Names []string
+	Code  string
+	Doc   string
+	// Deprecated is the deprecation notice, if the declaration is deprecated
+	Deprecated string
+	SourceURL  string
+}
+
+// Function is a function or a method.
+type Function struct {
+	// Name is the name of the function, without any receiver
+	Name      string
+	Signature string
+	Doc       string
+	// Deprecated is the deprecation notice, if the function is deprecated
+	Deprecated string
+	SourceURL  string
+}
+
+type Type struct {
+	Name        string
+	Declaration string
+	Doc         string
+	// Deprecated is the deprecation notice, if the type is deprecated
+	Deprecated string
+	SourceURL  string
+	Constants  []Declaration
+	Variables  []Declaration
+	// Functions are the functions that return the type, such as constructors
+	Functions []Function
+	Methods   []Function
+}
+
+type ExamplesRequest struct {
+	Package string
+}
+
+// Example is a runnable example from the documentation of a package.
+type Example struct {
+	// Name is the name of the example function, such as "ExampleFoo_bar"
+	Name string
+	// Symbol is the symbol the example belongs to, such as "Foo" or "Foo.Bar",
+	// or empty if it is an example of the package
+	Symbol string
+	Doc    string
+	Code   string
+	// Output is the expected output of the example, if it has any
+	Output string
+}
+
+// unitDocElements are the expected elements of the documentation section of a package's page,
+// which is only on the pages of packages.
+var unitDocElements = []expectedElement{
+	{selector: ".UnitHeader-titleHeading"},
+	{selector: ".Documentation", ifPresent: "#section-documentation"},
+}
+
+func (c *client) Documentation(req DocumentationRequest) (*Documentation, error) {
+	return c.DocumentationContext(context.Background(), req)
+}
+
+func (c *client) DocumentationContext(ctx context.Context, req DocumentationRequest) (*Documentation, error) {
+	col := c.newCollector(ctx, EndpointPackage)
+	doc := &Documentation{Package: req.Package}
+	errs := &ErrorList{}
+
+	col.OnHTML(".Documentation-overview", func(e *colly.HTMLElement) {
+		doc.Overview = docText(e.DOM)
+	})
+	col.OnHTML(".Documentation-constants", func(e *colly.HTMLElement) {
+		doc.Constants = parseDeclarations(e, e.DOM)
+	})
+	col.OnHTML(".Documentation-variables", func(e *colly.HTMLElement) {
+		doc.Variables = parseDeclarations(e, e.DOM)
+	})
+	col.OnHTML(".Documentation-function", func(e *colly.HTMLElement) {
+		f, err := parseFunction(e, e.DOM)
+		if err != nil {
+			errs.Errs = append(errs.Errs, &ParseError{Package: req.Package, Selector: ".Documentation-function", Err: err})
+			return
+		}
+		doc.Functions = append(doc.Functions, f)
+	})
+	col.OnHTML(".Documentation-type", func(e *colly.HTMLElement) {
+		header := e.DOM.Find("h4").First()
+		name, _ := header.Attr("id")
+		if name == "" {
+			errs.Errs = append(errs.Errs, &ParseError{
+				Package:  req.Package,
+				Selector: ".Documentation-type h4",
+				Err:      fmt.Errorf("found type without a name for '%s', this probably indicates a parsing bug", req.Package),
+			})
+			return
+		}
+		t := Type{
+			Name:        name,
+			Declaration: declarationCode(e.DOM.Find(".Documentation-declaration").First()),
+			Doc:         docText(e.DOM),
+			SourceURL:   sourceURL(e, header),
+		}
+		t.Deprecated = deprecationNotice(t.Doc)
+		e.DOM.Find(".Documentation-typeConstant").Each(func(i int, s *goquery.Selection) {
+			t.Constants = append(t.Constants, parseDeclarations(e, s)...)
+		})
+		e.DOM.Find(".Documentation-typeVariable").Each(func(i int, s *goquery.Selection) {
+			t.Variables = append(t.Variables, parseDeclarations(e, s)...)
+		})
+		e.DOM.Find(".Documentation-typeFunc").Each(func(i int, s *goquery.Selection) {
+			f, err := parseFunction(e, s)
+			if err != nil {
+				errs.Errs = append(errs.Errs, &ParseError{Package: req.Package, Selector: ".Documentation-typeFunc", Err: fmt.Errorf("parsing function of type '%s': %w", name, err)})
+				return
+			}
+			t.Functions = append(t.Functions, f)
+		})
+		e.DOM.Find(".Documentation-typeMethod").Each(func(i int, s *goquery.Selection) {
+			f, err := parseFunction(e, s)
+			if err != nil {
+				errs.Errs = append(errs.Errs, &ParseError{Package: req.Package, Selector: ".Documentation-typeMethod", Err: fmt.Errorf("parsing method of type '%s': %w", name, err)})
+				return
+			}
+			f.Name = strings.TrimPrefix(f.Name, name+".")
+			t.Methods = append(t.Methods, f)
+		})
+		doc.Types = append(doc.Types, t)
+	})
+
+	c.expectElements(col, req.Package, errs, unitDocElements...)
+	col.OnError(func(r *colly.Response, e error) {
+		errs.Errs = append(errs.Errs, c.responseError(r, e))
+	})
+	if err := visit(col, fmt.Sprintf("%s/%s", c.baseURL, req.Package)); err != nil {
+		errs.Errs = append(errs.Errs, err)
+	}
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+	c.handleParseErrors(errs)
+	if len(errs.Errs) != 0 {
+		return nil, errs
+	}
+	return doc, nil
+}
+
+func (c *client) Examples(req ExamplesRequest) ([]Example, error) {
+	return c.ExamplesContext(context.Background(), req)
+}
+
+func (c *client) ExamplesContext(ctx context.Context, req ExamplesRequest) ([]Example, error) {
+	col := c.newCollector(ctx, EndpointPackage)
+	var examples []Example
+	errs := &ErrorList{}
+
+	col.OnHTML(".Documentation-exampleDetails", func(e *colly.HTMLElement) {
+		id := e.Attr("id")
+		name, symbol, ok := parseExampleID(id)
+		if !ok {
+			errs.Errs = append(errs.Errs, &ParseError{
+				Package:  req.Package,
+				Selector: ".Documentation-exampleDetails",
+				Err:      fmt.Errorf("unable to parse example ID '%s' of '%s'", id, req.Package),
+			})
+			return
+		}
+		body := e.DOM.Find(".Documentation-exampleDetailsBody")
+		// the code and output are also in pre elements, so only paragraphs are part of the doc
+		var paragraphs []string
+		body.ChildrenFiltered("p").Each(func(i int, p *goquery.Selection) {
+			paragraphs = append(paragraphs, strings.TrimSpace(p.Text()))
+		})
+		examples = append(examples, Example{
+			Name:   name,
+			Symbol: symbol,
+			Doc:    strings.Join(paragraphs, "\n\n"),
+			Code:   strings.TrimSpace(body.Find(".Documentation-exampleCode").Text()),
+			Output: strings.TrimSpace(body.Find(".Documentation-exampleOutput").Text()),
+		})
+	})
+
+	c.expectElements(col, req.Package, errs, unitDocElements...)
+	col.OnError(func(r *colly.Response, e error) {
+		errs.Errs = append(errs.Errs, c.responseError(r, e))
+	})
+	if err := visit(col, fmt.Sprintf("%s/%s", c.baseURL, req.Package)); err != nil {
+		errs.Errs = append(errs.Errs, err)
+	}
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+	c.handleParseErrors(errs)
+	if len(errs.Errs) != 0 {
+		return nil, errs
+	}
+	return examples, nil
+}
+
+// parseExampleID parses the ID of an example element into the name of the example function and its symbol.
+// The IDs look like "example-package", "example-Foo", or "example-Foo.Bar-Suffix",
+// for the example functions "Example", "ExampleFoo", and "ExampleFoo_Bar_suffix".
+func parseExampleID(id string) (name, symbol string, ok bool) {
+	if !strings.HasPrefix(id, "example-") {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(id, "example-"), "-", 2)
+	if parts[0] == "" {
+		return "", "", false
+	}
+	name = "Example"
+	if parts[0] != "package" {
+		symbol = parts[0]
+		name += strings.ReplaceAll(symbol, ".", "_")
+	}
+	if len(parts) == 2 {
+		suffix := parts[1]
+		name += "_" + strings.ToLower(suffix[:1]) + suffix[1:]
+	}
+	return name, symbol, true
+}
+
+// docText returns the doc comment in s, as paragraphs separated by blank lines.
+// Deprecated symbols are collapsed on the page, so their docs are nested in the deprecation details.
+func docText(s *goquery.Selection) string {
+	var paragraphs []string
+	s.Children().Each(func(i int, child *goquery.Selection) {
+		switch {
+		case child.Is("p"), child.Is("pre"):
+			paragraphs = append(paragraphs, strings.TrimSpace(child.Text()))
+		case child.Is("h4") && s.HasClass("Documentation-overview"):
+			// the overview can have headings, but elsewhere h4s are the symbol headers
+			paragraphs = append(paragraphs, strings.TrimSpace(child.Text()))
+		case child.HasClass("Documentation-deprecatedDetails"), child.HasClass("Documentation-deprecatedBody"):
+			if text := docText(child); text != "" {
+				paragraphs = append(paragraphs, text)
+			}
+		}
+	})
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// deprecationNotice returns the text of the "Deprecated: " paragraph of a doc comment, if there is one.
+func deprecationNotice(doc string) string {
+	for _, paragraph := range strings.Split(doc, "\n\n") {
+		if strings.HasPrefix(paragraph, "Deprecated: ") {
+			return strings.TrimSpace(strings.TrimPrefix(paragraph, "Deprecated: "))
+		}
+	}
+	return ""
+}
+
+func declarationCode(s *goquery.Selection) string {
+	return strings.TrimSpace(s.Find("pre").Text())
+}
+
+func sourceURL(e *colly.HTMLElement, s *goquery.Selection) string {
+	href, ok := s.Find("a.Documentation-source").Attr("href")
+	if !ok {
+		return ""
+	}
+	// colly's AbsoluteURL drops the fragment, which holds the line number
+	u, err := e.Request.URL.Parse(href)
+	if err != nil {
+		return ""
+	}
+	return u.String()
+}
+
+// parseDeclarations parses a sequence of declarations, each followed by its doc comment.
+func parseDeclarations(e *colly.HTMLElement, s *goquery.Selection) []Declaration {
+	var decls []Declaration
+	var docs []string
+	finish := func() {
+		if len(decls) == 0 {
+			return
+		}
+		d := &decls[len(decls)-1]
+		d.Doc = strings.Join(docs, "\n\n")
+		d.Deprecated = deprecationNotice(d.Doc)
+		docs = nil
+	}
+	s.Children().Each(func(i int, child *goquery.Selection) {
+		switch {
+		case child.HasClass("Documentation-declaration"):
+			finish()
+			d := Declaration{
+				Code:      declarationCode(child),
+				SourceURL: sourceURL(e, child),
+			}
+			child.Find("[data-kind]").Each(func(i int, name *goquery.Selection) {
+				if id, ok := name.Attr("id"); ok {
+					d.Names = append(d.Names, id)
+				}
+			})
+			decls = append(decls, d)
+		case child.Is("p"), child.Is("pre"):
+			docs = append(docs, strings.TrimSpace(child.Text()))
+		}
+	})
+	finish()
+	return decls
+}
+
+func parseFunction(e *colly.HTMLElement, s *goquery.Selection) (Function, error) {
+	header := s.Find("h4").First()
+	name, _ := header.Attr("id")
+	if name == "" {
+		return Function{}, fmt.Errorf("found function without a name, this probably indicates a parsing bug")
+	}
+	f := Function{
+		Name:      name,
+		Signature: declarationCode(s.Find(".Documentation-declaration").First()),
+		Doc:       docText(s),
+		SourceURL: sourceURL(e, header),
+	}
+	f.Deprecated = deprecationNotice(f.Doc)
+	return f, nil
+}
@@ -0,0 +1,314 @@
+package pkggodevclient
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const documentationHTML = `
+<html><body>
+<div class="Documentation-content js-docContent">
+<section class="Documentation-overview">
+  <h3 tabindex="-1" id="pkg-overview" class="Documentation-overviewHeader">Overview</h3>
+  <p>Package foo does things.</p>
+  <h4 id="hdr-Usage">Usage</h4>
+  <pre>foo.Bar()</pre>
+</section>
+<h3 tabindex="-1" id="pkg-constants" class="Documentation-constantsHeader">Constants</h3>
+<section class="Documentation-constants">
+  <div class="Documentation-declaration">
+    <a class="Documentation-source" href="https://github.com/foo/foo/blob/v1.0.0/foo.go#L10">View Source</a>
+    <pre>const (
+	<span id="A" data-kind="constant">A</span> = 1
+	<span id="B" data-kind="constant">B</span> = 2
+)</pre>
+  </div>
+  <p>A and B are numbers.</p>
+  <div class="Documentation-declaration">
+    <pre>const <span id="C" data-kind="constant">C</span> = 3</pre>
+  </div>
+  <p>C is a number.</p>
+  <p>Deprecated: use A instead.</p>
+</section>
+<h3 tabindex="-1" id="pkg-variables" class="Documentation-variablesHeader">Variables</h3>
+<section class="Documentation-variables">
+  <div class="Documentation-declaration">
+    <pre>var <span id="ErrFoo" data-kind="variable">ErrFoo</span> = errors.New("foo")</pre>
+  </div>
+</section>
+<h3 tabindex="-1" id="pkg-functions" class="Documentation-functionsHeader">Functions</h3>
+<section class="Documentation-functions">
+  <div class="Documentation-function">
+    <h4 tabindex="-1" id="Do" data-kind="function" class="Documentation-functionHeader">
+      <span>func <a class="Documentation-source" href="https://github.com/foo/foo/blob/v1.0.0/foo.go#L20">Do</a></span>
+    </h4>
+    <div class="Documentation-declaration"><pre>func Do(x int) error</pre></div>
+    <p>Do does x.</p>
+  </div>
+  <div class="Documentation-function">
+    <details class="Documentation-deprecatedDetails js-deprecatedDetails">
+      <summary>
+        <h4 tabindex="-1" id="Old" data-kind="function" class="Documentation-functionHeader">
+          <span>func <a class="Documentation-source" href="/src/foo.go#L30">Old</a></span>
+        </h4>
+        <span class="Documentation-deprecatedTag">deprecated</span>
+      </summary>
+      <div class="Documentation-deprecatedBody">
+        <div class="Documentation-declaration"><pre>func Old()</pre></div>
+        <p>Old is old.</p>
+        <p>Deprecated: use Do.</p>
+      </div>
+    </details>
+  </div>
+</section>
+<h3 tabindex="-1" id="pkg-types" class="Documentation-typesHeader">Types</h3>
+<section class="Documentation-types">
+  <div class="Documentation-type">
+    <h4 tabindex="-1" id="Bar" data-kind="type" class="Documentation-typeHeader">
+      <span>type <a class="Documentation-source" href="https://github.com/foo/foo/blob/v1.0.0/bar.go#L5">Bar</a></span>
+    </h4>
+    <div class="Documentation-declaration"><pre>type Bar struct {
+	X int
+}</pre></div>
+    <p>Bar is a bar.</p>
+    <div class="Documentation-typeConstant">
+      <div class="Documentation-declaration">
+        <pre>const <span id="DefaultBar" data-kind="constant">DefaultBar</span> Bar = 1</pre>
+      </div>
+      <p>DefaultBar is the default.</p>
+    </div>
+    <div class="Documentation-typeFunc">
+      <h4 tabindex="-1" id="NewBar" data-kind="function" class="Documentation-typeFuncHeader">
+        <span>func <a class="Documentation-source" href="https://github.com/foo/foo/blob/v1.0.0/bar.go#L10">NewBar</a></span>
+      </h4>
+      <div class="Documentation-declaration"><pre>func NewBar() *Bar</pre></div>
+      <p>NewBar returns a Bar.</p>
+    </div>
+    <div class="Documentation-typeMethod">
+      <h4 tabindex="-1" id="Bar.Baz" data-kind="method" class="Documentation-typeMethodHeader">
+        <span>func (*Bar) <a class="Documentation-source" href="https://github.com/foo/foo/blob/v1.0.0/bar.go#L15">Baz</a></span>
+      </h4>
+      <div class="Documentation-declaration"><pre>func (b *Bar) Baz() string</pre></div>
+      <p>Baz returns baz.</p>
+    </div>
+  </div>
+</section>
+</div>
+</body></html>`
+
+func TestClient_Documentation(t *testing.T) {
+	cases := []struct {
+		name              string
+		html              string
+		httpCode          int
+		expectErrContains string
+		expectDoc         func(addr string) *Documentation
+	}{
+		{
+			name: "happy case",
+			html: documentationHTML,
+			expectDoc: func(addr string) *Documentation {
+				return &Documentation{
+					Package:  "somepackage",
+					Overview: "Package foo does things.\n\nUsage\n\nfoo.Bar()",
+					Constants: []Declaration{
+						{
+							Names:     []string{"A", "B"},
+							Code:      "const (\n\tA = 1\n\tB = 2\n)",
+							Doc:       "A and B are numbers.",
+							SourceURL: "https://github.com/foo/foo/blob/v1.0.0/foo.go#L10",
+						},
+						{
+							Names:      []string{"C"},
+							Code:       "const C = 3",
+							Doc:        "C is a number.\n\nDeprecated: use A instead.",
+							Deprecated: "use A instead.",
+						},
+					},
+					Variables: []Declaration{
+						{Names: []string{"ErrFoo"}, Code: `var ErrFoo = errors.New("foo")`},
+					},
+					Functions: []Function{
+						{
+							Name:      "Do",
+							Signature: "func Do(x int) error",
+							Doc:       "Do does x.",
+							SourceURL: "https://github.com/foo/foo/blob/v1.0.0/foo.go#L20",
+						},
+						{
+							Name:       "Old",
+							Signature:  "func Old()",
+							Doc:        "Old is old.\n\nDeprecated: use Do.",
+							Deprecated: "use Do.",
+							SourceURL:  "http://" + addr + "/src/foo.go#L30",
+						},
+					},
+					Types: []Type{
+						{
+							Name:        "Bar",
+							Declaration: "type Bar struct {\n\tX int\n}",
+							Doc:         "Bar is a bar.",
+							SourceURL:   "https://github.com/foo/foo/blob/v1.0.0/bar.go#L5",
+							Constants: []Declaration{
+								{Names: []string{"DefaultBar"}, Code: "const DefaultBar Bar = 1", Doc: "DefaultBar is the default."},
+							},
+							Functions: []Function{
+								{
+									Name:      "NewBar",
+									Signature: "func NewBar() *Bar",
+									Doc:       "NewBar returns a Bar.",
+									SourceURL: "https://github.com/foo/foo/blob/v1.0.0/bar.go#L10",
+								},
+							},
+							Methods: []Function{
+								{
+									Name:      "Baz",
+									Signature: "func (b *Bar) Baz() string",
+									Doc:       "Baz returns baz.",
+									SourceURL: "https://github.com/foo/foo/blob/v1.0.0/bar.go#L15",
+								},
+							},
+						},
+					},
+				}
+			},
+		},
+		{
+			name: "no documentation",
+			html: "<html></html>",
+			expectDoc: func(addr string) *Documentation {
+				return &Documentation{Package: "somepackage"}
+			},
+		},
+		{
+			name:              "returns an error if a function has no name",
+			html:              `<html><div class="Documentation-function"><h4></h4></div></html>`,
+			expectErrContains: "found function without a name",
+		},
+		{
+			name:              "returns an error if HTTP req fails",
+			httpCode:          500,
+			expectErrContains: "Internal Server Error",
+		},
+		{
+			name:              "returns error on 404",
+			httpCode:          404,
+			expectErrContains: "not found on pkg.go.dev",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+				if c.httpCode != 0 {
+					rw.WriteHeader(c.httpCode)
+					return
+				}
+				rw.Write([]byte(c.html))
+			}, func(addr string) {
+				client := New(WithBaseURL("http://" + addr))
+				doc, err := client.Documentation(DocumentationRequest{
+					Package: "somepackage",
+				})
+				if c.expectErrContains != "" {
+					assert.Contains(t, err.Error(), c.expectErrContains)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, c.expectDoc(addr), doc)
+			})
+		})
+	}
+}
+
+func TestClient_Examples(t *testing.T) {
+	cases := []struct {
+		name              string
+		html              string
+		httpCode          int
+		expectErrContains string
+		expectExamples    []Example
+	}{
+		{
+			name: "happy case",
+			html: `
+<html><body>
+<section class="Documentation-overview">
+  <p>Package foo does things.</p>
+  <details tabindex="-1" id="example-package" class="Documentation-exampleDetails js-exampleContainer">
+    <summary class="Documentation-exampleDetailsHeader">Example <span class="Documentation-exampleLink"><a href="#example-package">¶</a></span></summary>
+    <div class="Documentation-exampleDetailsBody">
+      <textarea class="Documentation-exampleCode code" spellcheck="false">package main
+
+func main() {
+	foo.Do()
+}
+</textarea>
+      <pre><span class="Documentation-exampleOutputLabel">Output:</span>
+<span class="Documentation-exampleOutput">done
+</span></pre>
+    </div>
+  </details>
+</section>
+<div class="Documentation-typeMethod">
+  <details tabindex="-1" id="example-Bar.Baz-WithOptions" class="Documentation-exampleDetails js-exampleContainer">
+    <summary class="Documentation-exampleDetailsHeader">Example (WithOptions)</summary>
+    <div class="Documentation-exampleDetailsBody">
+      <p>This example shows options.</p>
+      <pre class="Documentation-exampleCode">b.Baz()</pre>
+    </div>
+  </details>
+</div>
+</body></html>`,
+			expectExamples: []Example{
+				{Name: "Example", Code: "package main\n\nfunc main() {\n\tfoo.Do()\n}", Output: "done"},
+				{Name: "ExampleBar_Baz_withOptions", Symbol: "Bar.Baz", Doc: "This example shows options.", Code: "b.Baz()"},
+			},
+		},
+		{
+			name:           "no examples",
+			html:           "<html></html>",
+			expectExamples: nil,
+		},
+		{
+			name:              "returns an error if an example has an unknown ID",
+			html:              `<html><details id="foo" class="Documentation-exampleDetails"></details></html>`,
+			expectErrContains: "unable to parse example ID 'foo' of 'somepackage'",
+		},
+		{
+			name:              "returns an error if HTTP req fails",
+			httpCode:          500,
+			expectErrContains: "Internal Server Error",
+		},
+		{
+			name:              "returns error on 404",
+			httpCode:          404,
+			expectErrContains: "not found on pkg.go.dev",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+				if c.httpCode != 0 {
+					rw.WriteHeader(c.httpCode)
+					return
+				}
+				rw.Write([]byte(c.html))
+			}, func(addr string) {
+				client := New(WithBaseURL("http://" + addr))
+				examples, err := client.Examples(ExamplesRequest{
+					Package: "somepackage",
+				})
+				if c.expectErrContains != "" {
+					assert.Contains(t, err.Error(), c.expectErrContains)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, c.expectExamples, examples)
+			})
+		})
+	}
+}
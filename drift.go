@@ -0,0 +1,123 @@
+package pkggodevclient
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// ParseMode controls how strictly pages are parsed.
+type ParseMode string
+
+const (
+	// ParseModeDefault fails if an element can't be parsed, but ignores missing and unexpected elements.
+	ParseModeDefault ParseMode = "default"
+	// ParseModeStrict validates the expected elements of each page,
+	// and fails with a DriftError for each missing or unexpected element.
+	ParseModeStrict ParseMode = "strict"
+	// ParseModeLenient validates the expected elements of each page like ParseModeStrict,
+	// but returns whatever could be parsed, and reports parse and drift errors as warnings instead of failing.
+	ParseModeLenient ParseMode = "lenient"
+)
+
+// DriftKind is the kind of difference between a page and what the client expects.
+type DriftKind string
+
+const (
+	DriftMissing    DriftKind = "missing"
+	DriftUnexpected DriftKind = "unexpected"
+)
+
+// DriftError reports an element of a page that is missing or different than expected,
+// which usually means that pkg.go.dev changed its markup.
+type DriftError struct {
+	Package  string
+	Selector string
+	Kind     DriftKind
+	// Expected is the expected text of the element, if the element has one
+	Expected string
+	// Found is the text of the unexpected element
+	Found string
+}
+
+func (e *DriftError) Error() string {
+	switch {
+	case e.Kind == DriftMissing && e.Expected != "":
+		return fmt.Sprintf("page of '%s' has no element '%s' with text '%s'", e.Package, e.Selector, e.Expected)
+	case e.Kind == DriftMissing:
+		return fmt.Sprintf("page of '%s' has no element '%s'", e.Package, e.Selector)
+	case e.Expected == "":
+		return fmt.Sprintf("page of '%s' has unexpected element '%s' with text '%s'", e.Package, e.Selector, e.Found)
+	}
+	return fmt.Sprintf("page of '%s' has element '%s' with text '%s', expected '%s'", e.Package, e.Selector, e.Found, e.Expected)
+}
+
+// expectedElement is an element that every page of some kind is expected to have.
+type expectedElement struct {
+	selector string
+	// textPrefix is the expected start of the element's text, if any
+	textPrefix string
+	// ifPresent limits the expectation to pages that have an element matching this selector, if it is set,
+	// for sections that pages omit when they would be empty
+	ifPresent string
+}
+
+func (c *client) checksDrift() bool {
+	return c.parseMode == ParseModeStrict || c.parseMode == ParseModeLenient
+}
+
+// expectElements adds a DriftError to errs for each expected element that is missing from the page, if drift is checked.
+func (c *client) expectElements(col *colly.Collector, pkg string, errs *ErrorList, elements ...expectedElement) {
+	if !c.checksDrift() {
+		return
+	}
+	col.OnHTML("html", func(e *colly.HTMLElement) {
+		for _, el := range elements {
+			if el.ifPresent != "" && e.DOM.Find(el.ifPresent).Length() == 0 {
+				continue
+			}
+			s := e.DOM.Find(el.selector)
+			if s.Length() == 0 {
+				errs.Errs = append(errs.Errs, &DriftError{Package: pkg, Selector: el.selector, Kind: DriftMissing})
+				continue
+			}
+			text := strings.Join(strings.Fields(s.First().Text()), " ")
+			if !strings.HasPrefix(text, el.textPrefix) {
+				errs.Errs = append(errs.Errs, &DriftError{
+					Package:  pkg,
+					Selector: el.selector,
+					Kind:     DriftUnexpected,
+					Expected: el.textPrefix,
+					Found:    text,
+				})
+			}
+		}
+	})
+}
+
+// handleParseErrors removes parse and drift errors from errs in lenient mode, and reports them as warnings instead.
+func (c *client) handleParseErrors(errs *ErrorList) {
+	if c.parseMode != ParseModeLenient {
+		return
+	}
+	var remaining []error
+	for _, err := range errs.Errs {
+		var parseErr *ParseError
+		var driftErr *DriftError
+		if errors.As(err, &parseErr) || errors.As(err, &driftErr) {
+			c.warn(err)
+			continue
+		}
+		remaining = append(remaining, err)
+	}
+	errs.Errs = remaining
+}
+
+// warn passes an ignored error to the warning handler, if there is one.
+func (c *client) warn(err error) {
+	if c.warningHandler != nil {
+		c.warningHandler(err)
+	}
+}
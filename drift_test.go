@@ -0,0 +1,301 @@
+package pkggodevclient
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const driftedPackageHTML = `
+<html>
+<div class="UnitHeader-titleHeading">Heading</div>
+<div>package</div>
+<div>module</div>
+<div data-test-id="UnitHeader-version"><div>Version: v1.0.0</div></div>
+<div data-test-id="UnitHeader-commitTime">Released: Feb 3, 2000</div>
+<div data-test-id="UnitHeader-licenses"><div>MIT</div></div>
+<div class="UnitMeta"><ul>
+  <li><img alt="checked"/>Valid go.mod file <details><summary>?</summary>The Go module system...</details></li>
+  <li><img alt="checked"/>Redistributable license</li>
+  <li><img alt="checked"/>Signed version</li>
+</ul></div>
+</html>`
+
+func TestClient_DescribePackageDrift(t *testing.T) {
+	cases := []struct {
+		name              string
+		mode              ParseMode
+		expectErrs        []error
+		expectWarnings    []error
+		expectPackage     *Package
+		expectErrContains string
+	}{
+		{
+			name: "default mode ignores drift",
+			mode: ParseModeDefault,
+			// the published date can't be parsed, which is an error in every mode except lenient
+			expectErrContains: "parsing date 'Released: Feb 3, 2000'",
+		},
+		{
+			name: "strict mode reports drift",
+			mode: ParseModeStrict,
+			expectErrs: []error{
+				&DriftError{Package: "somepackage", Selector: ".UnitMeta li", Kind: DriftUnexpected, Found: "Signed version"},
+				&DriftError{Package: "somepackage", Selector: ".UnitMeta li", Kind: DriftMissing, Expected: "Tagged version"},
+				&DriftError{Package: "somepackage", Selector: ".UnitMeta li", Kind: DriftMissing, Expected: "Stable version"},
+				&DriftError{Package: "somepackage", Selector: "[data-test-id=UnitHeader-commitTime]", Kind: DriftUnexpected, Expected: "Published:", Found: "Released: Feb 3, 2000"},
+				&DriftError{Package: "somepackage", Selector: ".UnitMeta-repo", Kind: DriftMissing},
+			},
+		},
+		{
+			name: "lenient mode returns partial data with warnings",
+			mode: ParseModeLenient,
+			expectWarnings: []error{
+				&DriftError{Package: "somepackage", Selector: ".UnitMeta li", Kind: DriftUnexpected, Found: "Signed version"},
+				&DriftError{Package: "somepackage", Selector: ".UnitMeta li", Kind: DriftMissing, Expected: "Tagged version"},
+				&DriftError{Package: "somepackage", Selector: ".UnitMeta li", Kind: DriftMissing, Expected: "Stable version"},
+				&DriftError{Package: "somepackage", Selector: "[data-test-id=UnitHeader-commitTime]", Kind: DriftUnexpected, Expected: "Published:", Found: "Released: Feb 3, 2000"},
+				&DriftError{Package: "somepackage", Selector: ".UnitMeta-repo", Kind: DriftMissing},
+			},
+			expectPackage: &Package{
+				Package:                   "somepackage",
+				IsPackage:                 true,
+				IsModule:                  true,
+				Version:                   "v1.0.0",
+				SemVer:                    &SemVer{Major: 1},
+				License:                   "MIT",
+				HasValidGoModFile:         true,
+				HasRedistributableLicense: true,
+				Checks: map[string]bool{
+					"Valid go.mod file":       true,
+					"Redistributable license": true,
+					"Signed version":          true,
+				},
+				CheckDetails: map[string]CheckDetail{
+					"Valid go.mod file":       {Tooltip: "The Go module system...", URL: ""},
+					"Redistributable license": {},
+					"Signed version":          {},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+				rw.Write([]byte(driftedPackageHTML))
+			}, func(addr string) {
+				var mut sync.Mutex
+				var warnings []error
+				client := New(
+					WithBaseURL("http://"+addr),
+					WithParseMode(c.mode),
+					WithWarningHandler(func(warning error) {
+						mut.Lock()
+						defer mut.Unlock()
+						warnings = append(warnings, warning)
+					}),
+				)
+				pkg, err := client.DescribePackage(DescribePackageRequest{Package: "somepackage"})
+
+				switch {
+				case c.expectErrContains != "":
+					assert.Contains(t, err.Error(), c.expectErrContains)
+					var driftErr *DriftError
+					assert.False(t, errors.As(err, &driftErr))
+				case c.expectErrs != nil:
+					var driftErrs []error
+					for _, err := range err.(*ErrorList).Errs {
+						if _, ok := err.(*DriftError); ok {
+							driftErrs = append(driftErrs, err)
+						}
+					}
+					assert.ElementsMatch(t, c.expectErrs, driftErrs)
+					var driftErr *DriftError
+					assert.ErrorAs(t, err, &driftErr)
+				default:
+					assert.NoError(t, err)
+					assert.Equal(t, c.expectPackage, pkg)
+				}
+
+				var driftWarnings []error
+				for _, w := range warnings {
+					if _, ok := w.(*DriftError); ok {
+						driftWarnings = append(driftWarnings, w)
+					}
+				}
+				assert.ElementsMatch(t, c.expectWarnings, driftWarnings)
+			})
+		})
+	}
+}
+
+func TestClient_VersionsLenient(t *testing.T) {
+	html := `
+<html><div class="Versions-list">
+<div class="Version-tag"><a class="js-versionLink">v1.1.0</a></div>
+<details class="Version-details"><summary class="Version-summary">sometime</summary></details>
+<div class="Version-tag"><a class="js-versionLink">v1.0.0</a></div>
+<div class="Version-commitTime">Jan 2, 2000</div>
+</div></html>`
+
+	withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte(html))
+	}, func(addr string) {
+		_, err := New(WithBaseURL("http://" + addr)).Versions(VersionsRequest{Package: "somepackage"})
+		assert.Contains(t, err.Error(), "parsing '.Version-summary' of 'somepackage': parsing date 'sometime'")
+
+		var warnings []error
+		client := New(
+			WithBaseURL("http://"+addr),
+			WithParseMode(ParseModeLenient),
+			WithWarningHandler(func(warning error) { warnings = append(warnings, warning) }),
+		)
+		versions, err := client.Versions(VersionsRequest{Package: "somepackage"})
+		assert.NoError(t, err)
+		assert.Equal(t, []Version{
+			{FullVersion: "v1.1.0", SemVer: &SemVer{Major: 1, Minor: 1}},
+			{FullVersion: "v1.0.0", SemVer: &SemVer{Major: 1}, Date: "2000-01-02", DateTime: date(2000, 1, 2)},
+		}, versions.Versions)
+		if assert.Len(t, warnings, 1) {
+			assert.Contains(t, warnings[0].Error(), "parsing date 'sometime'")
+		}
+	})
+}
+
+func TestClient_EndpointDrift(t *testing.T) {
+	cases := []struct {
+		name string
+		html string
+		// call calls the endpoint and returns its error
+		call       func(c Client) error
+		expectErrs []error
+	}{
+		{
+			name: "licenses without license sections",
+			html: `<html><div data-test-id="UnitHeader-licenses"><a href="?tab=licenses">MIT</a></div><div class="Licenses"></div></html>`,
+			call: func(c Client) error {
+				_, err := c.Licenses(LicensesRequest{Package: "somepackage"})
+				return err
+			},
+			expectErrs: []error{&DriftError{Package: "somepackage", Selector: ".License", Kind: DriftMissing}},
+		},
+		{
+			name: "licenses of a module without licenses",
+			html: `<html><div data-test-id="UnitHeader-licenses">None detected</div></html>`,
+			call: func(c Client) error {
+				_, err := c.Licenses(LicensesRequest{Package: "somepackage"})
+				return err
+			},
+		},
+		{
+			name: "documentation without its section",
+			html: `<html><div class="UnitHeader-titleHeading">somepackage</div><h2 id="section-documentation">Documentation</h2><div class="Docs"></div></html>`,
+			call: func(c Client) error {
+				_, err := c.Documentation(DocumentationRequest{Package: "somepackage"})
+				return err
+			},
+			expectErrs: []error{&DriftError{Package: "somepackage", Selector: ".Documentation", Kind: DriftMissing}},
+		},
+		{
+			name: "examples of a page that isn't a package page",
+			html: `<html><div class="Header"></div></html>`,
+			call: func(c Client) error {
+				_, err := c.Examples(ExamplesRequest{Package: "somepackage"})
+				return err
+			},
+			expectErrs: []error{&DriftError{Package: "somepackage", Selector: ".UnitHeader-titleHeading", Kind: DriftMissing}},
+		},
+		{
+			name: "directories without their table",
+			html: `<html><div class="UnitHeader-titleHeading">somepackage</div><h2 id="section-directories">Directories</h2><ul class="Dirs"></ul></html>`,
+			call: func(c Client) error {
+				_, err := c.Directories(DirectoriesRequest{Package: "somepackage"})
+				return err
+			},
+			expectErrs: []error{&DriftError{Package: "somepackage", Selector: ".UnitDirectories-table", Kind: DriftMissing}},
+		},
+		{
+			name: "package without directories",
+			html: `<html><div class="UnitHeader-titleHeading">somepackage</div></html>`,
+			call: func(c Client) error {
+				_, err := c.Directories(DirectoriesRequest{Package: "somepackage"})
+				return err
+			},
+		},
+		{
+			name: "README without its content",
+			html: `<html><div class="UnitHeader-titleHeading">somepackage</div><h2 id="section-readme">README</h2><div class="Readme">hello</div></html>`,
+			call: func(c Client) error {
+				_, err := c.Readme(ReadmeRequest{Package: "somepackage"})
+				return err
+			},
+			expectErrs: []error{&DriftError{Package: "somepackage", Selector: ".UnitReadme-content", Kind: DriftMissing}},
+		},
+		{
+			name: "search results without snippets",
+			html: `<html><div data-test-id="results-total">2 results</div><div class="Snippet">foo</div><div class="Snippet">bar</div></html>`,
+			call: func(c Client) error {
+				_, err := c.Search(SearchRequest{Query: "somequery"})
+				return err
+			},
+			expectErrs: []error{&DriftError{Package: "somequery", Selector: ".LegacySearchSnippet", Kind: DriftMissing}},
+		},
+		{
+			name: "search results without a result count",
+			html: `<html><div class="LegacySearchSnippet"><div data-test-id="snippet-title">foo</div>
+<div class="SearchSnippet-infoLabel">
+  <span data-test-id="snippet-version">v1.0.0</span>
+  <span data-test-id="snippet-published">Feb 3, 2000</span>
+  <span data-test-id="snippet-importedby">10</span>
+  <span data-test-id="snippet-license">MIT</span>
+</div></div></html>`,
+			call: func(c Client) error {
+				_, err := c.Search(SearchRequest{Query: "somequery"})
+				return err
+			},
+			expectErrs: []error{&DriftError{Package: "somequery", Selector: "[data-test-id=results-total]", Kind: DriftMissing}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+				rw.Write([]byte(c.html))
+			}, func(addr string) {
+				// the page is valid for the endpoint in default mode
+				err := c.call(New(WithBaseURL("http://" + addr)))
+				var driftErr *DriftError
+				assert.False(t, errors.As(err, &driftErr))
+
+				err = c.call(New(WithBaseURL("http://"+addr), WithParseMode(ParseModeStrict)))
+				if c.expectErrs == nil {
+					assert.False(t, errors.As(err, &driftErr))
+					return
+				}
+				var driftErrs []error
+				if errList, ok := err.(*ErrorList); assert.True(t, ok, "%v", err) {
+					for _, err := range errList.Errs {
+						if _, ok := err.(*DriftError); ok {
+							driftErrs = append(driftErrs, err)
+						}
+					}
+				}
+				assert.ElementsMatch(t, c.expectErrs, driftErrs)
+
+				var warnings []error
+				client := New(
+					WithBaseURL("http://"+addr),
+					WithParseMode(ParseModeLenient),
+					WithWarningHandler(func(warning error) { warnings = append(warnings, warning) }),
+				)
+				err = c.call(client)
+				assert.False(t, errors.As(err, &driftErr))
+				assert.ElementsMatch(t, c.expectErrs, warnings)
+			})
+		})
+	}
+}
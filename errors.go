@@ -0,0 +1,138 @@
+package pkggodevclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+)
+
+var ErrNotFound = errors.New("not found on pkg.go.dev")
+
+// ErrCanceled is returned when a request's context is canceled or its deadline is exceeded.
+// The returned error also wraps the context's error, so it can be checked with either.
+var ErrCanceled = errors.New("request canceled")
+
+type canceledError struct {
+	err error
+}
+
+func (e *canceledError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrCanceled, e.err)
+}
+
+func (e *canceledError) Is(target error) bool {
+	return target == ErrCanceled
+}
+
+func (e *canceledError) Unwrap() error {
+	return e.err
+}
+
+// ctxErr returns an error wrapping ErrCanceled if the context is done, otherwise nil.
+func ctxErr(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return &canceledError{err: err}
+	}
+	return nil
+}
+
+// ErrorList is returned when there are multiple errors, such as when multiple elements of a page can't be parsed.
+// errors.Is and errors.As match any of its errors.
+type ErrorList struct {
+	Errs []error
+}
+
+func (e *ErrorList) Error() string {
+	return fmt.Sprintf("errors: %v", e.Errs)
+}
+
+func (e *ErrorList) Is(target error) bool {
+	for _, err := range e.Errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *ErrorList) As(target interface{}) bool {
+	for _, err := range e.Errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPError is returned when pkg.go.dev responds with an unsuccessful status code.
+// If the status code is 404, it wraps ErrNotFound.
+type HTTPError struct {
+	StatusCode int
+	URL        string
+	Err        error
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("making req to %s: %s", e.URL, e.Err)
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitError is returned when pkg.go.dev responds with 429 Too Many Requests, after any retries.
+// It wraps the HTTPError of the response.
+type RateLimitError struct {
+	*HTTPError
+	// RetryAfter is how long pkg.go.dev asked to wait before making more requests, or zero if it didn't say.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return e.HTTPError
+}
+
+// ParseError is returned when a page doesn't have the expected structure,
+// which usually means that pkg.go.dev changed its markup.
+type ParseError struct {
+	// Package is the package whose page couldn't be parsed, or the query for search pages.
+	Package string
+	// Selector is the CSS selector of the element that couldn't be parsed.
+	Selector string
+	Err      error
+}
+
+func (e *ParseError) Error() string {
+	if e.Package == "" {
+		return fmt.Sprintf("parsing '%s': %s", e.Selector, e.Err)
+	}
+	return fmt.Sprintf("parsing '%s' of '%s': %s", e.Selector, e.Package, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// responseError returns the error for a failed request.
+func (c *client) responseError(r *colly.Response, err error) error {
+	if r.StatusCode == 0 {
+		// the request failed without a response
+		return fmt.Errorf("making req to %s: %w", r.Request.URL.String(), err)
+	}
+	httpErr := &HTTPError{StatusCode: r.StatusCode, URL: r.Request.URL.String(), Err: err}
+	switch r.StatusCode {
+	case http.StatusNotFound:
+		httpErr.Err = ErrNotFound
+	case http.StatusTooManyRequests:
+		rateLimitErr := &RateLimitError{HTTPError: httpErr}
+		if r.Headers != nil {
+			rateLimitErr.RetryAfter, _ = parseRetryAfter(r.Headers.Get("Retry-After"), c.now())
+		}
+		return rateLimitErr
+	}
+	return httpErr
+}
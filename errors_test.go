@@ -0,0 +1,112 @@
+package pkggodevclient
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorList(t *testing.T) {
+	parseErr := &ParseError{Package: "somepackage", Selector: ".Foo", Err: errors.New("bad foo")}
+	errs := &ErrorList{Errs: []error{parseErr, &HTTPError{StatusCode: 404, URL: "http://example.com", Err: ErrNotFound}}}
+
+	assert.ErrorIs(t, errs, ErrNotFound)
+	assert.NotErrorIs(t, errs, ErrCanceled)
+
+	var actualParseErr *ParseError
+	assert.ErrorAs(t, errs, &actualParseErr)
+	assert.Equal(t, parseErr, actualParseErr)
+
+	var rateLimitErr *RateLimitError
+	assert.False(t, errors.As(errs, &rateLimitErr))
+}
+
+func TestClient_Errors(t *testing.T) {
+	now := time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name             string
+		handler          http.HandlerFunc
+		expectStatusCode int
+		expectNotFound   bool
+		expectRetryAfter time.Duration
+		expectParseErr   *ParseError
+	}{
+		{
+			name: "server error",
+			handler: func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(http.StatusInternalServerError)
+			},
+			expectStatusCode: 500,
+		},
+		{
+			name: "not found",
+			handler: func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(http.StatusNotFound)
+			},
+			expectStatusCode: 404,
+			expectNotFound:   true,
+		},
+		{
+			name: "rate limited",
+			handler: func(rw http.ResponseWriter, r *http.Request) {
+				rw.Header().Set("Retry-After", "30")
+				rw.WriteHeader(http.StatusTooManyRequests)
+			},
+			expectStatusCode: 429,
+			expectRetryAfter: 30 * time.Second,
+		},
+		{
+			name: "parse error",
+			handler: func(rw http.ResponseWriter, r *http.Request) {
+				rw.Write([]byte(`<html><section class="License"><h2></h2></section></html>`))
+			},
+			expectParseErr: &ParseError{
+				Package:  "somepackage",
+				Selector: ".License h2",
+				Err:      errors.New("found license without a name for 'somepackage', this probably indicates a parsing bug"),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			withHTTPServer("/", c.handler, func(addr string) {
+				client := New(WithBaseURL("http://"+addr), WithClock(func() time.Time { return now }))
+				_, err := client.Licenses(LicensesRequest{Package: "somepackage"})
+
+				var parseErr *ParseError
+				if c.expectParseErr != nil {
+					assert.ErrorAs(t, err, &parseErr)
+					assert.Equal(t, c.expectParseErr, parseErr)
+					assert.Contains(t, err.Error(), "parsing '.License h2' of 'somepackage': found license without a name")
+					return
+				}
+				assert.False(t, errors.As(err, &parseErr))
+
+				var httpErr *HTTPError
+				assert.ErrorAs(t, err, &httpErr)
+				assert.Equal(t, c.expectStatusCode, httpErr.StatusCode)
+				assert.Equal(t, "http://"+addr+"/somepackage?tab=licenses", httpErr.URL)
+
+				assert.Equal(t, c.expectNotFound, errors.Is(err, ErrNotFound))
+
+				var rateLimitErr *RateLimitError
+				assert.Equal(t, c.expectStatusCode == 429, errors.As(err, &rateLimitErr))
+				if rateLimitErr != nil {
+					assert.Equal(t, c.expectRetryAfter, rateLimitErr.RetryAfter)
+				}
+			})
+		})
+	}
+}
+
+func TestParseError_Error(t *testing.T) {
+	err := &ParseError{Package: "somepackage", Selector: ".License h2", Err: errors.New("boom")}
+	assert.Equal(t, "parsing '.License h2' of 'somepackage': boom", err.Error())
+
+	err = &ParseError{Selector: ".License h2", Err: errors.New("boom")}
+	assert.Equal(t, "parsing '.License h2': boom", err.Error())
+}
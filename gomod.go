@@ -0,0 +1,83 @@
+package pkggodevclient
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// goVersion returns the go directive of the go.mod file of the module that provides pkg at the given version.
+// pkg.go.dev doesn't show the go.mod file, so it is fetched from the module proxy.
+// The proxy doesn't know which module provides a package, so like the go command,
+// this tries each prefix of the package path, starting with the longest.
+// A version in pkg, such as "example.com/mod@v1.2.3", is ignored.
+func (c *client) goVersion(ctx context.Context, pkg, version string) (string, error) {
+	if version == "" {
+		return "", fmt.Errorf("unable to find the go.mod file of '%s' without its version", pkg)
+	}
+	pkg, _, _ = strings.Cut(pkg, "@")
+	for modPath := pkg; modPath != "." && modPath != "/"; modPath = path.Dir(modPath) {
+		goMod, found, err := c.fetchGoMod(ctx, modPath, version)
+		if err != nil {
+			return "", err
+		}
+		if found {
+			return parseGoDirective(goMod)
+		}
+	}
+	return "", fmt.Errorf("unable to find the go.mod file of '%s' at %s: %w", pkg, version, ErrNotFound)
+}
+
+func (c *client) fetchGoMod(ctx context.Context, modPath, version string) (goMod []byte, found bool, err error) {
+	col := c.newCollector(ctx, EndpointGoMod)
+	errs := &ErrorList{}
+
+	col.OnResponse(func(r *colly.Response) {
+		goMod = r.Body
+		found = true
+	})
+	col.OnError(func(r *colly.Response, e error) {
+		// the proxy responds with 404 or 410 if the path is not a module
+		if r.StatusCode == 404 || r.StatusCode == 410 {
+			return
+		}
+		errs.Errs = append(errs.Errs, c.responseError(r, e))
+	})
+	escapedPath, err := module.EscapePath(modPath)
+	if err != nil {
+		// a prefix of a package path that isn't a valid module path can't be a module
+		return nil, false, nil
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, false, err
+	}
+	url := fmt.Sprintf("%s/%s/@v/%s.mod", c.proxyURL, escapedPath, escapedVersion)
+	if err := visit(col, url); err != nil {
+		errs.Errs = append(errs.Errs, err)
+	}
+	if err := ctxErr(ctx); err != nil {
+		return nil, false, err
+	}
+	if len(errs.Errs) != 0 {
+		return nil, false, errs
+	}
+	return goMod, found, nil
+}
+
+// parseGoDirective returns the version in the go directive of a go.mod file, or an empty string if it has none.
+func parseGoDirective(goMod []byte) (string, error) {
+	f, err := modfile.ParseLax("go.mod", goMod, nil)
+	if err != nil {
+		return "", fmt.Errorf("parsing go.mod: %w", err)
+	}
+	if f.Go == nil {
+		return "", nil
+	}
+	return f.Go.Version, nil
+}
@@ -0,0 +1,479 @@
+// Package pkggodevtest provides an in-memory implementation of pkggodevclient.Client for use in tests.
+package pkggodevtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	pkggodevclient "github.com/guseggert/pkggodev-client"
+	"golang.org/x/mod/modfile"
+)
+
+var _ pkggodevclient.Client = (*Client)(nil)
+
+// Client is a fake pkggodevclient.Client that returns canned responses.
+// Packages without a canned response return pkggodevclient.ErrNotFound,
+// and queries without canned search results return no results.
+// It is safe for concurrent use.
+type Client struct {
+	mu            sync.Mutex
+	packages      map[string]pkggodevclient.Package
+	versions      map[string]pkggodevclient.Versions
+	importedBy    map[string]pkggodevclient.ImportedBy
+	imports       map[string]pkggodevclient.Imports
+	licenses      map[string][]pkggodevclient.License
+	searchResults map[string]pkggodevclient.SearchResults
+	documentation map[string]pkggodevclient.Documentation
+	examples      map[string][]pkggodevclient.Example
+	directories   map[string][]pkggodevclient.Directory
+	readmes       map[string]pkggodevclient.Readme
+	auditReports  map[string]pkggodevclient.AuditReport
+	errs          map[string]error
+}
+
+// New returns a fake client with no canned responses.
+func New() *Client {
+	return &Client{
+		packages:      map[string]pkggodevclient.Package{},
+		versions:      map[string]pkggodevclient.Versions{},
+		importedBy:    map[string]pkggodevclient.ImportedBy{},
+		imports:       map[string]pkggodevclient.Imports{},
+		licenses:      map[string][]pkggodevclient.License{},
+		searchResults: map[string]pkggodevclient.SearchResults{},
+		documentation: map[string]pkggodevclient.Documentation{},
+		examples:      map[string][]pkggodevclient.Example{},
+		directories:   map[string][]pkggodevclient.Directory{},
+		readmes:       map[string]pkggodevclient.Readme{},
+		auditReports:  map[string]pkggodevclient.AuditReport{},
+		errs:          map[string]error{},
+	}
+}
+
+// SetPackage sets the response of DescribePackage for p.Package.
+func (c *Client) SetPackage(p pkggodevclient.Package) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.packages[p.Package] = p
+	return c
+}
+
+// SetVersions sets the response of Versions for v.Package.
+func (c *Client) SetVersions(v pkggodevclient.Versions) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.versions[v.Package] = v
+	return c
+}
+
+// SetImportedBy sets the response of ImportedBy for i.Package.
+func (c *Client) SetImportedBy(i pkggodevclient.ImportedBy) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.importedBy[i.Package] = i
+	return c
+}
+
+// SetImports sets the response of Imports for i.Package.
+func (c *Client) SetImports(i pkggodevclient.Imports) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.imports[i.Package] = i
+	return c
+}
+
+// SetLicenses sets the response of Licenses for pkg.
+func (c *Client) SetLicenses(pkg string, licenses []pkggodevclient.License) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.licenses[pkg] = licenses
+	return c
+}
+
+// SetSearchResults sets the response of Search for query.
+// The results are truncated to the request's limit, if it is set.
+func (c *Client) SetSearchResults(query string, results pkggodevclient.SearchResults) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.searchResults[query] = results
+	return c
+}
+
+// SetDocumentation sets the response of Documentation for d.Package.
+func (c *Client) SetDocumentation(d pkggodevclient.Documentation) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.documentation[d.Package] = d
+	return c
+}
+
+// SetExamples sets the response of Examples for pkg.
+func (c *Client) SetExamples(pkg string, examples []pkggodevclient.Example) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.examples[pkg] = examples
+	return c
+}
+
+// SetDirectories sets the response of Directories for pkg.
+func (c *Client) SetDirectories(pkg string, dirs []pkggodevclient.Directory) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.directories[pkg] = dirs
+	return c
+}
+
+// SetReadme sets the response of Readme for r.Package.
+// The README is returned as is, regardless of the requested format.
+func (c *Client) SetReadme(r pkggodevclient.Readme) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readmes[r.Package] = r
+	return c
+}
+
+// SetAuditReport sets the response of Audit for go.mod files of r.Module.
+func (c *Client) SetAuditReport(r pkggodevclient.AuditReport) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.auditReports[r.Module] = r
+	return c
+}
+
+// SetError makes every method return err when called for the given package or search query.
+// A nil err removes a previously set error.
+func (c *Client) SetError(key string, err error) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		delete(c.errs, key)
+		return c
+	}
+	c.errs[key] = err
+	return c
+}
+
+type canceledError struct {
+	err error
+}
+
+func (e *canceledError) Error() string {
+	return fmt.Sprintf("%s: %s", pkggodevclient.ErrCanceled, e.err)
+}
+
+func (e *canceledError) Is(target error) bool {
+	return target == pkggodevclient.ErrCanceled
+}
+
+func (e *canceledError) Unwrap() error {
+	return e.err
+}
+
+// check returns the error that a call for key should return, if any.
+// The caller must hold c.mu.
+func (c *Client) check(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return &canceledError{err: err}
+	}
+	return c.errs[key]
+}
+
+func (c *Client) ImportedBy(req pkggodevclient.ImportedByRequest) (*pkggodevclient.ImportedBy, error) {
+	return c.ImportedByContext(context.Background(), req)
+}
+
+func (c *Client) ImportedByContext(ctx context.Context, req pkggodevclient.ImportedByRequest) (*pkggodevclient.ImportedBy, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.check(ctx, req.Package); err != nil {
+		return nil, err
+	}
+	i, ok := c.importedBy[req.Package]
+	if !ok {
+		return nil, pkggodevclient.ErrNotFound
+	}
+	return &i, nil
+}
+
+func (c *Client) DescribePackage(req pkggodevclient.DescribePackageRequest) (*pkggodevclient.Package, error) {
+	return c.DescribePackageContext(context.Background(), req)
+}
+
+func (c *Client) DescribePackageContext(ctx context.Context, req pkggodevclient.DescribePackageRequest) (*pkggodevclient.Package, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.check(ctx, req.Package); err != nil {
+		return nil, err
+	}
+	p, ok := c.packages[req.Package]
+	if !ok {
+		return nil, pkggodevclient.ErrNotFound
+	}
+	return &p, nil
+}
+
+func (c *Client) Versions(req pkggodevclient.VersionsRequest) (*pkggodevclient.Versions, error) {
+	return c.VersionsContext(context.Background(), req)
+}
+
+func (c *Client) VersionsContext(ctx context.Context, req pkggodevclient.VersionsRequest) (*pkggodevclient.Versions, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.check(ctx, req.Package); err != nil {
+		return nil, err
+	}
+	v, ok := c.versions[req.Package]
+	if !ok {
+		return nil, pkggodevclient.ErrNotFound
+	}
+	return &v, nil
+}
+
+func (c *Client) Search(req pkggodevclient.SearchRequest) (*pkggodevclient.SearchResults, error) {
+	return c.SearchContext(context.Background(), req)
+}
+
+func (c *Client) SearchContext(ctx context.Context, req pkggodevclient.SearchRequest) (*pkggodevclient.SearchResults, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.check(ctx, req.Query); err != nil {
+		return nil, err
+	}
+	results := c.searchResults[req.Query]
+	if req.Limit > 0 && len(results.Results) > req.Limit {
+		results.Results = results.Results[:req.Limit]
+	}
+	if req.Limit > 0 && len(results.SymbolResults) > req.Limit {
+		results.SymbolResults = results.SymbolResults[:req.Limit]
+	}
+	return &results, nil
+}
+
+func (c *Client) SearchIterator(ctx context.Context, req pkggodevclient.SearchRequest) pkggodevclient.SearchIterator {
+	results, err := c.SearchContext(ctx, req)
+	if err != nil {
+		return &searchIterator{err: err}
+	}
+	return &searchIterator{
+		results: *results,
+		symbols: req.Mode == pkggodevclient.SearchModeSymbol,
+		idx:     -1,
+	}
+}
+
+// searchIterator iterates over canned search results.
+type searchIterator struct {
+	results pkggodevclient.SearchResults
+	// symbols is true if the iterator is over the symbol results
+	symbols bool
+	idx     int
+	err     error
+}
+
+func (it *searchIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.idx++
+	if it.symbols {
+		return it.idx < len(it.results.SymbolResults)
+	}
+	return it.idx < len(it.results.Results)
+}
+
+func (it *searchIterator) Result() pkggodevclient.SearchResult {
+	if it.idx >= 0 && it.idx < len(it.results.Results) {
+		return it.results.Results[it.idx]
+	}
+	return pkggodevclient.SearchResult{}
+}
+
+func (it *searchIterator) SymbolResult() pkggodevclient.SymbolSearchResult {
+	if it.idx >= 0 && it.idx < len(it.results.SymbolResults) {
+		return it.results.SymbolResults[it.idx]
+	}
+	return pkggodevclient.SymbolSearchResult{}
+}
+
+func (it *searchIterator) Total() int {
+	return it.results.Total
+}
+
+func (it *searchIterator) Err() error {
+	return it.err
+}
+
+func (c *Client) Imports(req pkggodevclient.ImportsRequest) (*pkggodevclient.Imports, error) {
+	return c.ImportsContext(context.Background(), req)
+}
+
+func (c *Client) ImportsContext(ctx context.Context, req pkggodevclient.ImportsRequest) (*pkggodevclient.Imports, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.check(ctx, req.Package); err != nil {
+		return nil, err
+	}
+	i, ok := c.imports[req.Package]
+	if !ok {
+		return nil, pkggodevclient.ErrNotFound
+	}
+	return &i, nil
+}
+
+func (c *Client) Licenses(req pkggodevclient.LicensesRequest) ([]pkggodevclient.License, error) {
+	return c.LicensesContext(context.Background(), req)
+}
+
+func (c *Client) LicensesContext(ctx context.Context, req pkggodevclient.LicensesRequest) ([]pkggodevclient.License, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.check(ctx, req.Package); err != nil {
+		return nil, err
+	}
+	l, ok := c.licenses[req.Package]
+	if !ok {
+		return nil, pkggodevclient.ErrNotFound
+	}
+	return l, nil
+}
+
+func (c *Client) Documentation(req pkggodevclient.DocumentationRequest) (*pkggodevclient.Documentation, error) {
+	return c.DocumentationContext(context.Background(), req)
+}
+
+func (c *Client) DocumentationContext(ctx context.Context, req pkggodevclient.DocumentationRequest) (*pkggodevclient.Documentation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.check(ctx, req.Package); err != nil {
+		return nil, err
+	}
+	d, ok := c.documentation[req.Package]
+	if !ok {
+		return nil, pkggodevclient.ErrNotFound
+	}
+	return &d, nil
+}
+
+func (c *Client) Examples(req pkggodevclient.ExamplesRequest) ([]pkggodevclient.Example, error) {
+	return c.ExamplesContext(context.Background(), req)
+}
+
+func (c *Client) ExamplesContext(ctx context.Context, req pkggodevclient.ExamplesRequest) ([]pkggodevclient.Example, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.check(ctx, req.Package); err != nil {
+		return nil, err
+	}
+	e, ok := c.examples[req.Package]
+	if !ok {
+		return nil, pkggodevclient.ErrNotFound
+	}
+	return e, nil
+}
+
+func (c *Client) Directories(req pkggodevclient.DirectoriesRequest) ([]pkggodevclient.Directory, error) {
+	return c.DirectoriesContext(context.Background(), req)
+}
+
+func (c *Client) DirectoriesContext(ctx context.Context, req pkggodevclient.DirectoriesRequest) ([]pkggodevclient.Directory, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.check(ctx, req.Package); err != nil {
+		return nil, err
+	}
+	d, ok := c.directories[req.Package]
+	if !ok {
+		return nil, pkggodevclient.ErrNotFound
+	}
+	return d, nil
+}
+
+func (c *Client) Readme(req pkggodevclient.ReadmeRequest) (*pkggodevclient.Readme, error) {
+	return c.ReadmeContext(context.Background(), req)
+}
+
+func (c *Client) ReadmeContext(ctx context.Context, req pkggodevclient.ReadmeRequest) (*pkggodevclient.Readme, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.check(ctx, req.Package); err != nil {
+		return nil, err
+	}
+	r, ok := c.readmes[req.Package]
+	if !ok {
+		return nil, pkggodevclient.ErrNotFound
+	}
+	return &r, nil
+}
+
+// DescribePackageBatch sends the results in the order of reqs, ignoring concurrency.
+func (c *Client) DescribePackageBatch(ctx context.Context, reqs []pkggodevclient.DescribePackageRequest, concurrency int) <-chan pkggodevclient.DescribePackageResult {
+	results := make(chan pkggodevclient.DescribePackageResult)
+	go func() {
+		defer close(results)
+		for i, req := range reqs {
+			p, err := c.DescribePackageContext(ctx, req)
+			select {
+			case results <- pkggodevclient.DescribePackageResult{Index: i, Request: req, Package: p, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return results
+}
+
+// VersionsBatch sends the results in the order of reqs, ignoring concurrency.
+func (c *Client) VersionsBatch(ctx context.Context, reqs []pkggodevclient.VersionsRequest, concurrency int) <-chan pkggodevclient.VersionsResult {
+	results := make(chan pkggodevclient.VersionsResult)
+	go func() {
+		defer close(results)
+		for i, req := range reqs {
+			v, err := c.VersionsContext(ctx, req)
+			select {
+			case results <- pkggodevclient.VersionsResult{Index: i, Request: req, Versions: v, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return results
+}
+
+// ImportedByBatch sends the results in the order of reqs, ignoring concurrency.
+func (c *Client) ImportedByBatch(ctx context.Context, reqs []pkggodevclient.ImportedByRequest, concurrency int) <-chan pkggodevclient.ImportedByResult {
+	results := make(chan pkggodevclient.ImportedByResult)
+	go func() {
+		defer close(results)
+		for i, req := range reqs {
+			importedBy, err := c.ImportedByContext(ctx, req)
+			select {
+			case results <- pkggodevclient.ImportedByResult{Index: i, Request: req, ImportedBy: importedBy, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return results
+}
+
+func (c *Client) Audit(req pkggodevclient.AuditRequest) (*pkggodevclient.AuditReport, error) {
+	return c.AuditContext(context.Background(), req)
+}
+
+func (c *Client) AuditContext(ctx context.Context, req pkggodevclient.AuditRequest) (*pkggodevclient.AuditReport, error) {
+	module := modfile.ModulePath(req.GoMod)
+	if module == "" {
+		return nil, errors.New("parsing go.mod: no module directive")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.check(ctx, module); err != nil {
+		return nil, err
+	}
+	r, ok := c.auditReports[module]
+	if !ok {
+		return nil, pkggodevclient.ErrNotFound
+	}
+	return &r, nil
+}
@@ -0,0 +1,41 @@
+package pkggodevtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pkggodevclient "github.com/guseggert/pkggodev-client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	someErr := errors.New("some error")
+	c := New().
+		SetPackage(pkggodevclient.Package{Package: "foo", Version: "v1.0.0"}).
+		SetSearchResults("query", pkggodevclient.SearchResults{Results: []pkggodevclient.SearchResult{
+			{Package: "foo"},
+			{Package: "bar"},
+		}}).
+		SetError("broken", someErr)
+
+	pkg, err := c.DescribePackage(pkggodevclient.DescribePackageRequest{Package: "foo"})
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.0.0", pkg.Version)
+
+	_, err = c.DescribePackage(pkggodevclient.DescribePackageRequest{Package: "missing"})
+	assert.ErrorIs(t, err, pkggodevclient.ErrNotFound)
+
+	_, err = c.Versions(pkggodevclient.VersionsRequest{Package: "broken"})
+	assert.ErrorIs(t, err, someErr)
+
+	results, err := c.Search(pkggodevclient.SearchRequest{Query: "query", Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []pkggodevclient.SearchResult{{Package: "foo"}}, results.Results)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = c.DescribePackageContext(ctx, pkggodevclient.DescribePackageRequest{Package: "foo"})
+	assert.ErrorIs(t, err, pkggodevclient.ErrCanceled)
+	assert.ErrorIs(t, err, context.Canceled)
+}
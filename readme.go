@@ -0,0 +1,321 @@
+package pkggodevclient
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ReadmeFormat is the format to convert a README to.
+type ReadmeFormat string
+
+const (
+	ReadmeFormatMarkdown ReadmeFormat = "markdown"
+	ReadmeFormatText     ReadmeFormat = "text"
+)
+
+type ReadmeRequest struct {
+	Package string
+	// Format defaults to ReadmeFormatMarkdown.
+	Format ReadmeFormat
+}
+
+type Readme struct {
+	Package string
+	Format  ReadmeFormat
+	Content string
+}
+
+func (c *client) Readme(req ReadmeRequest) (*Readme, error) {
+	return c.ReadmeContext(context.Background(), req)
+}
+
+// ReadmeContext returns the README shown on the page of the package.
+// pkg.go.dev renders the README as HTML, which is converted back to the requested format,
+// so the result is not necessarily identical to the README in the repository.
+// If the package exists but has no README, the returned error wraps ErrNotFound.
+func (c *client) ReadmeContext(ctx context.Context, req ReadmeRequest) (*Readme, error) {
+	if req.Format == "" {
+		req.Format = ReadmeFormatMarkdown
+	}
+	if req.Format != ReadmeFormatMarkdown && req.Format != ReadmeFormatText {
+		return nil, fmt.Errorf("unknown README format '%s'", req.Format)
+	}
+
+	col := c.newCollector(ctx, EndpointPackage)
+	var readme *Readme
+	errs := &ErrorList{}
+
+	col.OnHTML(".UnitReadme-content", func(e *colly.HTMLElement) {
+		readme = &Readme{
+			Package: req.Package,
+			Format:  req.Format,
+			Content: renderReadme(e.DOM.Nodes[0], req.Format == ReadmeFormatMarkdown),
+		}
+	})
+
+	// packages without a README have no README section
+	c.expectElements(col, req.Package, errs,
+		expectedElement{selector: ".UnitHeader-titleHeading"},
+		expectedElement{selector: ".UnitReadme-content", ifPresent: "#section-readme"},
+	)
+	col.OnError(func(r *colly.Response, e error) {
+		errs.Errs = append(errs.Errs, c.responseError(r, e))
+	})
+	if err := visit(col, fmt.Sprintf("%s/%s", c.baseURL, req.Package)); err != nil {
+		errs.Errs = append(errs.Errs, err)
+	}
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+	c.handleParseErrors(errs)
+	if len(errs.Errs) != 0 {
+		return nil, errs
+	}
+	if readme == nil {
+		return nil, fmt.Errorf("no README for '%s': %w", req.Package, ErrNotFound)
+	}
+	return readme, nil
+}
+
+// renderReadme converts the HTML of a README to Markdown, or to plain text if markdown is false.
+// It only handles the elements that pkg.go.dev generates from Markdown, and ignores the markup of other elements.
+func renderReadme(n *html.Node, markdown bool) string {
+	return strings.Join(renderBlocks(n, markdown), "\n\n")
+}
+
+// blockRenderer renders block elements, and accumulates inline elements into paragraphs.
+type blockRenderer struct {
+	markdown bool
+	blocks   []string
+	inline   strings.Builder
+}
+
+func renderBlocks(n *html.Node, markdown bool) []string {
+	r := &blockRenderer{markdown: markdown}
+	r.children(n)
+	r.flush()
+	return r.blocks
+}
+
+var spacesRegexp = regexp.MustCompile(`[ \t]+`)
+
+func (r *blockRenderer) flush() {
+	lines := strings.Split(r.inline.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(spacesRegexp.ReplaceAllString(line, " "))
+	}
+	if s := strings.TrimSpace(strings.Join(lines, "\n")); s != "" {
+		r.blocks = append(r.blocks, s)
+	}
+	r.inline.Reset()
+}
+
+func (r *blockRenderer) block(s string) {
+	r.flush()
+	if s != "" {
+		r.blocks = append(r.blocks, s)
+	}
+}
+
+func (r *blockRenderer) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		r.node(c)
+	}
+}
+
+func (r *blockRenderer) node(n *html.Node) {
+	if n.Type == html.TextNode {
+		r.inline.WriteString(collapseSpace(n.Data))
+		return
+	}
+	if n.Type != html.ElementNode {
+		return
+	}
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		text := strings.TrimSpace(renderInline(n, r.markdown))
+		if r.markdown {
+			level := int(n.Data[1] - '0')
+			text = strings.Repeat("#", level) + " " + text
+		}
+		r.block(text)
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Details, atom.Summary:
+		r.flush()
+		r.children(n)
+		r.flush()
+	case atom.Pre:
+		code := strings.TrimRight(textContent(n), "\n")
+		if r.markdown {
+			code = "```" + codeLanguage(n) + "\n" + code + "\n```"
+		}
+		r.block(code)
+	case atom.Ul, atom.Ol:
+		r.block(renderList(n, r.markdown))
+	case atom.Blockquote:
+		prefix := "  "
+		if r.markdown {
+			prefix = "> "
+		}
+		r.block(prefixLines(renderReadme(n, r.markdown), prefix))
+	case atom.Hr:
+		if r.markdown {
+			r.block("---")
+		} else {
+			r.flush()
+		}
+	case atom.Table:
+		r.block(renderTable(n, r.markdown))
+	case atom.Script, atom.Style:
+	default:
+		r.inline.WriteString(renderInline(n, r.markdown))
+	}
+}
+
+func renderInline(n *html.Node, markdown bool) string {
+	if n.Type == html.TextNode {
+		return collapseSpace(n.Data)
+	}
+	if n.Type != html.ElementNode {
+		return ""
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(renderInline(c, markdown))
+	}
+	text := b.String()
+	if !markdown {
+		switch n.DataAtom {
+		case atom.Img:
+			return attr(n, "alt")
+		case atom.Br:
+			return "\n"
+		}
+		return text
+	}
+	switch n.DataAtom {
+	case atom.A:
+		href := attr(n, "href")
+		if href == "" || strings.TrimSpace(text) == "" {
+			return text
+		}
+		return "[" + strings.TrimSpace(text) + "](" + href + ")"
+	case atom.Img:
+		return "![" + attr(n, "alt") + "](" + attr(n, "src") + ")"
+	case atom.Code:
+		return "`" + textContent(n) + "`"
+	case atom.Strong, atom.B:
+		return "**" + text + "**"
+	case atom.Em, atom.I:
+		return "*" + text + "*"
+	case atom.Del, atom.S:
+		return "~~" + text + "~~"
+	case atom.Br:
+		return "\n"
+	}
+	return text
+}
+
+func renderList(n *html.Node, markdown bool) string {
+	var items []string
+	num := 1
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.DataAtom != atom.Li {
+			continue
+		}
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", num)
+			num++
+		}
+		// keep the items of nested lists together with the item text
+		content := strings.Join(renderBlocks(c, markdown), "\n")
+		items = append(items, marker+strings.ReplaceAll(content, "\n", "\n"+strings.Repeat(" ", len(marker))))
+	}
+	return strings.Join(items, "\n")
+}
+
+func renderTable(n *html.Node, markdown bool) string {
+	var rows [][]string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.DataAtom != atom.Tr {
+				walk(c)
+				continue
+			}
+			var row []string
+			for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+				if cell.DataAtom == atom.Th || cell.DataAtom == atom.Td {
+					row = append(row, strings.TrimSpace(renderInline(cell, markdown)))
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	walk(n)
+
+	var lines []string
+	for i, row := range rows {
+		if !markdown {
+			lines = append(lines, strings.Join(row, "  "))
+			continue
+		}
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", len(row)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
+
+// codeLanguage returns the language of a code block, which is in the class of its code element, such as "language-go".
+func codeLanguage(pre *html.Node) string {
+	for c := pre.FirstChild; c != nil; c = c.NextSibling {
+		if c.DataAtom != atom.Code {
+			continue
+		}
+		for _, class := range strings.Fields(attr(c, "class")) {
+			if strings.HasPrefix(class, "language-") {
+				return strings.TrimPrefix(class, "language-")
+			}
+		}
+	}
+	return ""
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+var whitespaceRegexp = regexp.MustCompile(`\s+`)
+
+// collapseSpace collapses whitespace like browsers do, keeping a single space at the start or end of the text.
+func collapseSpace(s string) string {
+	return whitespaceRegexp.ReplaceAllString(s, " ")
+}
+
+func prefixLines(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
@@ -0,0 +1,123 @@
+package pkggodevclient
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const readmeHTML = `
+<html><body>
+<section class="UnitReadme js-readme">
+  <h2 class="UnitReadme-title" id="section-readme">README</h2>
+  <div class="UnitReadme-content" data-test-id="Unit-readmeContent">
+    <div class="Overview-readmeContent js-readmeContent">
+      <h3 id="readme-foo">Foo</h3>
+      <p><a href="https://example.com/ci"><img src="https://example.com/badge.svg" alt="CI"></a></p>
+      <p>Foo is a <strong>fast</strong>
+        library for <em>doing</em> things with <code>foo.Do</code>.<br>See the <a href="https://example.com/docs">docs</a>.</p>
+      <h4 id="readme-install">Install</h4>
+      <pre><code class="language-sh">go get example.com/foo
+</code></pre>
+      <ul>
+        <li>one</li>
+        <li><p>two</p>
+          <ol><li>nested</li></ol>
+        </li>
+      </ul>
+      <blockquote><p>Note: it's great.</p></blockquote>
+      <table>
+        <thead><tr><th>Name</th><th>Value</th></tr></thead>
+        <tbody><tr><td>a</td><td>1</td></tr></tbody>
+      </table>
+    </div>
+  </div>
+</section>
+</body></html>`
+
+func TestClient_Readme(t *testing.T) {
+	cases := []struct {
+		name              string
+		html              string
+		httpCode          int
+		format            ReadmeFormat
+		expectErrIs       error
+		expectErrContains string
+		expectContent     string
+	}{
+		{
+			name: "markdown",
+			html: readmeHTML,
+			expectContent: "### Foo\n\n" +
+				"[![CI](https://example.com/badge.svg)](https://example.com/ci)\n\n" +
+				"Foo is a **fast** library for *doing* things with `foo.Do`.\nSee the [docs](https://example.com/docs).\n\n" +
+				"#### Install\n\n" +
+				"```sh\ngo get example.com/foo\n```\n\n" +
+				"- one\n- two\n  1. nested\n\n" +
+				"> Note: it's great.\n\n" +
+				"| Name | Value |\n| --- | --- |\n| a | 1 |",
+		},
+		{
+			name:   "text",
+			html:   readmeHTML,
+			format: ReadmeFormatText,
+			expectContent: "Foo\n\n" +
+				"CI\n\n" +
+				"Foo is a fast library for doing things with foo.Do.\nSee the docs.\n\n" +
+				"Install\n\n" +
+				"go get example.com/foo\n\n" +
+				"- one\n- two\n  1. nested\n\n" +
+				"  Note: it's great.\n\n" +
+				"Name  Value\na  1",
+		},
+		{
+			name:              "returns an error for an unknown format",
+			format:            "html",
+			expectErrContains: "unknown README format 'html'",
+		},
+		{
+			name:              "returns ErrNotFound if there is no README",
+			html:              "<html></html>",
+			expectErrIs:       ErrNotFound,
+			expectErrContains: "no README for 'somepackage'",
+		},
+		{
+			name:              "returns an error if HTTP req fails",
+			httpCode:          500,
+			expectErrContains: "Internal Server Error",
+		},
+		{
+			name:              "returns error on 404",
+			httpCode:          404,
+			expectErrContains: "not found on pkg.go.dev",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+				if c.httpCode != 0 {
+					rw.WriteHeader(c.httpCode)
+					return
+				}
+				rw.Write([]byte(c.html))
+			}, func(addr string) {
+				client := New(WithBaseURL("http://" + addr))
+				readme, err := client.Readme(ReadmeRequest{
+					Package: "somepackage",
+					Format:  c.format,
+				})
+				if c.expectErrIs != nil {
+					assert.ErrorIs(t, err, c.expectErrIs)
+				}
+				if c.expectErrContains != "" {
+					assert.Contains(t, err.Error(), c.expectErrContains)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, c.expectContent, readme.Content)
+			})
+		})
+	}
+}
@@ -0,0 +1,444 @@
+package pkggodevclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+)
+
+// SearchMode is the kind of thing to search for.
+type SearchMode string
+
+const (
+	SearchModePackage SearchMode = "package"
+	SearchModeSymbol  SearchMode = "symbol"
+)
+
+type SearchRequest struct {
+	Query string
+	// Limit is the maximum number of results to return, where zero means there is no limit.
+	Limit int
+	// Mode defaults to SearchModePackage.
+	// Package results are returned in SearchResults.Results, and symbol results in SearchResults.SymbolResults.
+	Mode SearchMode
+	// ResolveVersions resolves truncated pseudo-versions in the results, e.g. "v0.0.0-...-496545a",
+	// by looking up each of those packages. A result whose lookup fails keeps its truncated version and a nil SemVer,
+	// and the error is passed to the warning handler.
+	ResolveVersions bool
+	// ResolveConcurrency is the maximum number of concurrent lookups when resolving versions, which defaults to 4.
+	ResolveConcurrency int
+}
+
+type SearchResults struct {
+	// Total is the total number of results for the query according to pkg.go.dev, regardless of the limit.
+	Total         int
+	Results       []SearchResult
+	SymbolResults []SymbolSearchResult
+}
+
+type SearchResult struct {
+	Package string
+	Version string
+	// SemVer is the parsed Version, or nil if it is a truncated pseudo-version that wasn't resolved
+	SemVer        *SemVer
+	Published     string
+	PublishedTime time.Time
+	ImportedBy    int
+	License       string
+	Synopsis      string
+}
+
+type SymbolSearchResult struct {
+	// Symbol is qualified by its package name, e.g. "yaml.Unmarshal"
+	Symbol string
+	// Kind is e.g. "function", "type", "method", "field", "constant" or "variable"
+	Kind string
+	// Package is the path of the package that contains the symbol
+	Package   string
+	Signature string
+	Synopsis  string
+	Version   string
+	// SemVer is the parsed Version, or nil if it is a truncated pseudo-version that wasn't resolved
+	SemVer        *SemVer
+	Published     string
+	PublishedTime time.Time
+	ImportedBy    int
+	License       string
+}
+
+// SearchIterator iterates over search results, fetching each page of results when it's needed.
+// Iteration can be stopped at any point by no longer calling Next.
+//
+//	it := client.SearchIterator(ctx, req)
+//	for it.Next() {
+//		fmt.Println(it.Result().Package)
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type SearchIterator interface {
+	// Next advances to the next result, and returns false when there are no more results or an error occurred.
+	Next() bool
+	// Result returns the current result of a package search.
+	Result() SearchResult
+	// SymbolResult returns the current result of a symbol search.
+	SymbolResult() SymbolSearchResult
+	// Total returns the total number of results according to pkg.go.dev, which is known after the first call to Next.
+	Total() int
+	// Err returns the error that stopped the iteration, if any.
+	Err() error
+}
+
+func (c *client) Search(req SearchRequest) (*SearchResults, error) {
+	return c.SearchContext(context.Background(), req)
+}
+
+func (c *client) SearchContext(ctx context.Context, req SearchRequest) (*SearchResults, error) {
+	results := &SearchResults{}
+	it := c.SearchIterator(ctx, req)
+	for it.Next() {
+		if req.Mode == SearchModeSymbol {
+			results.SymbolResults = append(results.SymbolResults, it.SymbolResult())
+		} else {
+			results.Results = append(results.Results, it.Result())
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	results.Total = it.Total()
+	return results, nil
+}
+
+func (c *client) SearchIterator(ctx context.Context, req SearchRequest) SearchIterator {
+	if req.Mode == "" {
+		req.Mode = SearchModePackage
+	}
+	return &searchIterator{
+		ctx:      ctx,
+		client:   c,
+		req:      req,
+		morePage: true,
+	}
+}
+
+type searchIterator struct {
+	ctx    context.Context
+	client *client
+	req    SearchRequest
+
+	page     int
+	morePage bool
+	// results of the current page, or symbolResults for symbol searches
+	results       []SearchResult
+	symbolResults []SymbolSearchResult
+	// idx is the index of the current result in the current page
+	idx int
+	// returned is the number of results returned so far, for enforcing the limit
+	returned int
+	total    int
+	err      error
+}
+
+func (it *searchIterator) pageLen() int {
+	return len(it.results) + len(it.symbolResults)
+}
+
+func (it *searchIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.req.Limit > 0 && it.returned >= it.req.Limit {
+		return false
+	}
+	it.idx++
+	// pages can be empty, so keep fetching until there's a result or there are no more pages
+	for it.idx >= it.pageLen() {
+		if !it.morePage {
+			return false
+		}
+		it.page++
+		remaining := 0
+		if it.req.Limit > 0 {
+			remaining = it.req.Limit - it.returned
+		}
+		page, err := it.client.searchPage(it.ctx, it.req, it.page, remaining)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.results = page.results
+		it.symbolResults = page.symbolResults
+		it.total = page.total
+		it.morePage = page.morePages
+		it.idx = 0
+	}
+	it.returned++
+	return true
+}
+
+func (it *searchIterator) Result() SearchResult {
+	if it.idx < len(it.results) {
+		return it.results[it.idx]
+	}
+	return SearchResult{}
+}
+
+func (it *searchIterator) SymbolResult() SymbolSearchResult {
+	if it.idx < len(it.symbolResults) {
+		return it.symbolResults[it.idx]
+	}
+	return SymbolSearchResult{}
+}
+
+func (it *searchIterator) Total() int {
+	return it.total
+}
+
+func (it *searchIterator) Err() error {
+	return it.err
+}
+
+type searchPage struct {
+	results       []SearchResult
+	symbolResults []SymbolSearchResult
+	total         int
+	morePages     bool
+}
+
+var (
+	// e.g. "1 result", "2 results", ...
+	singlePageTotalRegexp = regexp.MustCompile(`^([\d,]+) results?$`)
+	// e.g. "1 - 25 of 125 results", "26-50 of 125 results", ...
+	multiPageTotalRegexp = regexp.MustCompile(`^[\d,]+\s*-\s*([\d,]+) of ([\d,]+) results?$`)
+)
+
+func parseResultCount(s string) (int, error) {
+	return strconv.Atoi(strings.ReplaceAll(s, ",", ""))
+}
+
+// searchPage fetches and parses a single page of search results, resolving versions if requested.
+// If limit is positive, the page's results are truncated to it, so that versions aren't resolved needlessly.
+func (c *client) searchPage(ctx context.Context, req SearchRequest, pageNum int, limit int) (*searchPage, error) {
+	col := c.newCollector(ctx, EndpointSearch)
+	page := &searchPage{}
+	errs := &ErrorList{}
+
+	// on page n, compute if we should follow to page n+1
+	col.OnHTML("[data-test-id=results-total]", func(e *colly.HTMLElement) {
+		resultsStr := strings.TrimSpace(e.Text)
+		if m := singlePageTotalRegexp.FindStringSubmatch(resultsStr); m != nil {
+			total, err := parseResultCount(m[1])
+			if err != nil {
+				errs.Errs = append(errs.Errs, &ParseError{Package: req.Query, Selector: "[data-test-id=results-total]", Err: err})
+				return
+			}
+			page.total = total
+			return
+		}
+		m := multiPageTotalRegexp.FindStringSubmatch(resultsStr)
+		if m == nil {
+			errs.Errs = append(errs.Errs, &ParseError{
+				Package:  req.Query,
+				Selector: "[data-test-id=results-total]",
+				Err:      fmt.Errorf("unable to parse result count '%s', this probably indicates a parsing bug", resultsStr),
+			})
+			return
+		}
+		upperBound, err := parseResultCount(m[1])
+		if err != nil {
+			errs.Errs = append(errs.Errs, &ParseError{Package: req.Query, Selector: "[data-test-id=results-total]", Err: err})
+			return
+		}
+		total, err := parseResultCount(m[2])
+		if err != nil {
+			errs.Errs = append(errs.Errs, &ParseError{Package: req.Query, Selector: "[data-test-id=results-total]", Err: err})
+			return
+		}
+		page.total = total
+		page.morePages = upperBound < total
+	})
+
+	var snippetSelector string
+	switch req.Mode {
+	case SearchModePackage:
+		snippetSelector = ".LegacySearchSnippet"
+		col.OnHTML(snippetSelector, func(e *colly.HTMLElement) {
+			pkg := strings.TrimSpace(e.DOM.Find("[data-test-id=snippet-title]").Text())
+			synopsis := strings.TrimSpace(e.DOM.Find(".SearchSnippet-synopsis").Text())
+			info, err := c.parseSnippetInfo(e.DOM.Find(".SearchSnippet-infoLabel"))
+			if err != nil {
+				errs.Errs = append(errs.Errs, &ParseError{Package: req.Query, Selector: ".SearchSnippet-infoLabel", Err: err})
+				return
+			}
+			result := SearchResult{
+				Package:       pkg,
+				Synopsis:      synopsis,
+				Version:       info.version,
+				Published:     info.published.Format(dateFormat),
+				PublishedTime: info.published,
+				ImportedBy:    info.importedBy,
+				License:       info.license,
+			}
+			page.results = append(page.results, result)
+		})
+	case SearchModeSymbol:
+		snippetSelector = ".SearchSnippet"
+		col.OnHTML(snippetSelector, func(e *colly.HTMLElement) {
+			// the title is the symbol followed by its package path in parentheses
+			title := e.DOM.Find("[data-test-id=snippet-title]")
+			pkgPath := title.Find(".SearchSnippet-header-path")
+			pkg := strings.Trim(strings.TrimSpace(pkgPath.Text()), "()")
+			symbol := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(title.Text()), strings.TrimSpace(pkgPath.Text())))
+			if symbol == "" || pkg == "" {
+				errs.Errs = append(errs.Errs, &ParseError{
+					Package:  req.Query,
+					Selector: "[data-test-id=snippet-title]",
+					Err:      fmt.Errorf("unable to find symbol and package in search result title '%s', this probably indicates a parsing bug", strings.TrimSpace(title.Text())),
+				})
+				return
+			}
+			info, err := c.parseSnippetInfo(e.DOM.Find(".SearchSnippet-infoLabel"))
+			if err != nil {
+				errs.Errs = append(errs.Errs, &ParseError{Package: req.Query, Selector: ".SearchSnippet-infoLabel", Err: err})
+				return
+			}
+			result := SymbolSearchResult{
+				Symbol:        symbol,
+				Kind:          strings.TrimSpace(e.DOM.Find(".SearchSnippet-symbolKind").Text()),
+				Package:       pkg,
+				Signature:     strings.TrimSpace(e.DOM.Find(".SearchSnippet-symbolCode").Text()),
+				Synopsis:      strings.TrimSpace(e.DOM.Find(".SearchSnippet-synopsis").Text()),
+				Version:       info.version,
+				Published:     info.published.Format(dateFormat),
+				PublishedTime: info.published,
+				ImportedBy:    info.importedBy,
+				License:       info.license,
+			}
+			page.symbolResults = append(page.symbolResults, result)
+		})
+	default:
+		return nil, fmt.Errorf("unknown search mode '%s'", req.Mode)
+	}
+
+	// pages without results have no result count
+	c.expectElements(col, req.Query, errs, expectedElement{selector: "[data-test-id=results-total]", ifPresent: snippetSelector})
+	col.OnError(func(r *colly.Response, e error) {
+		errs.Errs = append(errs.Errs, c.responseError(r, e))
+	})
+	if err := visit(col, fmt.Sprintf("%s/search?q=%s&m=%s&page=%d", c.baseURL, url.QueryEscape(req.Query), req.Mode, pageNum)); err != nil {
+		errs.Errs = append(errs.Errs, err)
+	}
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+	// the first page of a search with results must have some, or the markup of the results changed
+	if c.checksDrift() && len(errs.Errs) == 0 && pageNum == 1 && page.total > 0 && len(page.results) == 0 && len(page.symbolResults) == 0 {
+		errs.Errs = append(errs.Errs, &DriftError{Package: req.Query, Selector: snippetSelector, Kind: DriftMissing})
+	}
+	c.handleParseErrors(errs)
+	if len(errs.Errs) > 0 {
+		return nil, errs
+	}
+
+	if limit > 0 && len(page.results) > limit {
+		page.results = page.results[:limit]
+	}
+	if limit > 0 && len(page.symbolResults) > limit {
+		page.symbolResults = page.symbolResults[:limit]
+	}
+
+	if req.ResolveVersions {
+		var toResolve []versionToResolve
+		for i := range page.results {
+			toResolve = append(toResolve, versionToResolve{pkg: page.results[i].Package, version: &page.results[i].Version})
+		}
+		for i := range page.symbolResults {
+			toResolve = append(toResolve, versionToResolve{pkg: page.symbolResults[i].Package, version: &page.symbolResults[i].Version})
+		}
+		err := c.resolveVersions(ctx, toResolve, req.ResolveConcurrency)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i := range page.results {
+		page.results[i].SemVer = parseSemVer(page.results[i].Version)
+	}
+	for i := range page.symbolResults {
+		page.symbolResults[i].SemVer = parseSemVer(page.symbolResults[i].Version)
+	}
+
+	return page, nil
+}
+
+type snippetInfo struct {
+	version    string
+	published  time.Time
+	importedBy int
+	license    string
+}
+
+// parseSnippetInfo parses the info label that's shared by all kinds of search results.
+func (c *client) parseSnippetInfo(info *goquery.Selection) (snippetInfo, error) {
+	// pseudoversions are truncated and contain '...', so resolving them takes an additional lookup,
+	// which is only done if requested since it makes searches take much longer
+	version := strings.TrimSpace(info.Find("[data-test-id=snippet-version]").Text())
+
+	publishedDateStr := strings.TrimSpace(info.Find("[data-test-id=snippet-published]").Text())
+	published, err := c.parseDate(publishedDateStr)
+	if err != nil {
+		return snippetInfo{}, err
+	}
+	importedByWithCommas := strings.TrimSpace(info.Find("[data-test-id=snippet-importedby]").Text())
+	importedByStr := strings.ReplaceAll(importedByWithCommas, ",", "")
+	importedBy, err := strconv.Atoi(importedByStr)
+	if err != nil {
+		return snippetInfo{}, err
+	}
+	license := strings.TrimSpace(info.Find("[data-test-id=snippet-license]").Text())
+	return snippetInfo{
+		version:    version,
+		published:  published,
+		importedBy: importedBy,
+		license:    license,
+	}, nil
+}
+
+func isTruncatedPseudoVersion(version string) bool {
+	return strings.Contains(version, "...")
+}
+
+type versionToResolve struct {
+	pkg     string
+	version *string
+}
+
+// resolveVersions replaces truncated pseudo-versions with full ones, by describing each of those packages.
+// Resolving is optional, so a lookup that fails is passed to the warning handler and leaves its version truncated.
+func (c *client) resolveVersions(ctx context.Context, toResolve []versionToResolve, concurrency int) error {
+	var truncated []versionToResolve
+	for _, r := range toResolve {
+		if isTruncatedPseudoVersion(*r.version) {
+			truncated = append(truncated, r)
+		}
+	}
+	runBatch(ctx, len(truncated), concurrency, func(i int) {
+		r := truncated[i]
+		p, err := c.DescribePackageContext(ctx, DescribePackageRequest{Package: r.pkg})
+		if err != nil {
+			if ctxErr(ctx) == nil {
+				c.warn(fmt.Errorf("resolving version of '%s': %w", r.pkg, err))
+			}
+			return
+		}
+		*r.version = p.Version
+	})
+	return ctxErr(ctx)
+}
@@ -0,0 +1,270 @@
+package pkggodevclient
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func searchSnippet(pkg, version, published, importedBy string) string {
+	return `
+<div class="LegacySearchSnippet">
+  <h2><a data-test-id="snippet-title" href="/` + pkg + `">` + pkg + `</a></h2>
+  <p class="SearchSnippet-synopsis">Package ` + pkg + ` does things.</p>
+  <div class="SearchSnippet-infoLabel">
+    <span data-test-id="snippet-version">` + version + `</span>
+    <span data-test-id="snippet-published">` + published + `</span>
+    <span data-test-id="snippet-importedby">` + importedBy + `</span>
+    <span data-test-id="snippet-license">MIT</span>
+  </div>
+</div>`
+}
+
+func symbolSearchSnippet(symbol, kind, pkg string) string {
+	return `
+<div class="SearchSnippet">
+  <h2><a data-test-id="snippet-title" href="/` + pkg + `#` + symbol + `">` + symbol + ` <span class="SearchSnippet-header-path">(` + pkg + `)</span></a></h2>
+  <div class="SearchSnippet-symbolKind">` + kind + `</div>
+  <pre class="SearchSnippet-symbolCode">func ` + symbol + `()</pre>
+  <p class="SearchSnippet-synopsis">` + symbol + ` does things.</p>
+  <div class="SearchSnippet-infoLabel">
+    <span data-test-id="snippet-version">v1.0.0</span>
+    <span data-test-id="snippet-published">Feb 3, 2000</span>
+    <span data-test-id="snippet-importedby">10</span>
+    <span data-test-id="snippet-license">MIT</span>
+  </div>
+</div>`
+}
+
+func TestClient_Search(t *testing.T) {
+	cases := []struct {
+		name                string
+		req                 SearchRequest
+		pages               map[string]string
+		packages            map[string]string
+		expectErrContains   string
+		expectWarnings      []string
+		expectResults       []SearchResult
+		expectSymbolResults []SymbolSearchResult
+	}{
+		{
+			name: "happy case",
+			req:  SearchRequest{Query: "foo", Limit: 10},
+			pages: map[string]string{
+				"1": `<html><div data-test-id="results-total">1 - 2 of 3 results</div>` +
+					searchSnippet("foo", "v1.0.0", "Feb 3, 2000", "1,234") +
+					searchSnippet("bar", "v0.0.0-...-abcdef", "Feb 4, 2000", "5") + `</html>`,
+				"2": `<html><div data-test-id="results-total">3 - 3 of 3 results</div>` +
+					searchSnippet("baz", "v2.0.0", "Feb 5, 2000", "0") + `</html>`,
+			},
+			expectResults: []SearchResult{
+				{Package: "foo", Version: "v1.0.0", SemVer: &SemVer{Major: 1}, Published: "2000-02-03", PublishedTime: date(2000, 2, 3), ImportedBy: 1234, License: "MIT", Synopsis: "Package foo does things."},
+				{Package: "bar", Version: "v0.0.0-...-abcdef", Published: "2000-02-04", PublishedTime: date(2000, 2, 4), ImportedBy: 5, License: "MIT", Synopsis: "Package bar does things."},
+				{Package: "baz", Version: "v2.0.0", SemVer: &SemVer{Major: 2}, Published: "2000-02-05", PublishedTime: date(2000, 2, 5), ImportedBy: 0, License: "MIT", Synopsis: "Package baz does things."},
+			},
+		},
+		{
+			name: "stops at the limit",
+			req:  SearchRequest{Query: "foo", Limit: 1},
+			pages: map[string]string{
+				"1": `<html><div data-test-id="results-total">1 - 2 of 3 results</div>` +
+					searchSnippet("foo", "v1.0.0", "Feb 3, 2000", "1,234") +
+					searchSnippet("bar", "v0.0.0-...-abcdef", "Feb 4, 2000", "5") + `</html>`,
+			},
+			expectResults: []SearchResult{
+				{Package: "foo", Version: "v1.0.0", SemVer: &SemVer{Major: 1}, Published: "2000-02-03", PublishedTime: date(2000, 2, 3), ImportedBy: 1234, License: "MIT", Synopsis: "Package foo does things."},
+			},
+		},
+		{
+			name: "resolves pseudo-versions",
+			req:  SearchRequest{Query: "foo", Limit: 10, ResolveVersions: true},
+			pages: map[string]string{
+				"1": `<html><div data-test-id="results-total">2 results</div>` +
+					searchSnippet("foo", "v1.0.0", "Feb 3, 2000", "1,234") +
+					searchSnippet("bar", "v0.0.0-...-abcdef", "Feb 4, 2000", "5") + `</html>`,
+			},
+			packages: map[string]string{
+				"/bar": `<html>
+<div data-test-id="UnitHeader-version"><div>Version: v0.0.0-20000204000000-abcdef123456</div></div>
+<div class="UnitHeader-titleHeading">Heading</div><div>package</div>
+</html>`,
+			},
+			expectResults: []SearchResult{
+				{Package: "foo", Version: "v1.0.0", SemVer: &SemVer{Major: 1}, Published: "2000-02-03", PublishedTime: date(2000, 2, 3), ImportedBy: 1234, License: "MIT", Synopsis: "Package foo does things."},
+				{Package: "bar", Version: "v0.0.0-20000204000000-abcdef123456", SemVer: &SemVer{Prerelease: "20000204000000-abcdef123456", IsPseudo: true, PseudoTime: date(2000, 2, 4), PseudoRevision: "abcdef123456"}, Published: "2000-02-04", PublishedTime: date(2000, 2, 4), ImportedBy: 5, License: "MIT", Synopsis: "Package bar does things."},
+			},
+		},
+		{
+			name: "symbol search",
+			req:  SearchRequest{Query: "Unmarshal", Limit: 10, Mode: SearchModeSymbol},
+			pages: map[string]string{
+				"1": `<html><div data-test-id="results-total">2 results</div>` +
+					symbolSearchSnippet("yaml.Unmarshal", "function", "gopkg.in/yaml.v2") +
+					symbolSearchSnippet("json.Unmarshal", "function", "encoding/json") + `</html>`,
+			},
+			expectSymbolResults: []SymbolSearchResult{
+				{Symbol: "yaml.Unmarshal", Kind: "function", Package: "gopkg.in/yaml.v2", Signature: "func yaml.Unmarshal()", Synopsis: "yaml.Unmarshal does things.", Version: "v1.0.0", SemVer: &SemVer{Major: 1}, Published: "2000-02-03", PublishedTime: date(2000, 2, 3), ImportedBy: 10, License: "MIT"},
+				{Symbol: "json.Unmarshal", Kind: "function", Package: "encoding/json", Signature: "func json.Unmarshal()", Synopsis: "json.Unmarshal does things.", Version: "v1.0.0", SemVer: &SemVer{Major: 1}, Published: "2000-02-03", PublishedTime: date(2000, 2, 3), ImportedBy: 10, License: "MIT"},
+			},
+		},
+		{
+			name: "returns an error if a symbol result has no package",
+			req:  SearchRequest{Query: "Unmarshal", Limit: 10, Mode: SearchModeSymbol},
+			pages: map[string]string{
+				"1": `<html><div data-test-id="results-total">1 result</div>
+<div class="SearchSnippet"><h2><a data-test-id="snippet-title">yaml.Unmarshal</a></h2></div></html>`,
+			},
+			expectErrContains: "unable to find symbol and package in search result title 'yaml.Unmarshal'",
+		},
+		{
+			name:              "returns an error for unknown search modes",
+			req:               SearchRequest{Query: "foo", Mode: "foo"},
+			expectErrContains: "unknown search mode 'foo'",
+		},
+		{
+			name: "keeps the truncated version if a pseudo-version can't be resolved",
+			req:  SearchRequest{Query: "foo", Limit: 10, ResolveVersions: true},
+			pages: map[string]string{
+				"1": `<html><div data-test-id="results-total">2 results</div>` +
+					searchSnippet("bar", "v0.0.0-...-abcdef", "Feb 4, 2000", "5") +
+					searchSnippet("broken", "v0.0.0-...-123456", "Feb 5, 2000", "6") + `</html>`,
+			},
+			packages: map[string]string{
+				"/bar": `<html>
+<div data-test-id="UnitHeader-version"><div>Version: v0.0.0-20000204000000-abcdef123456</div></div>
+<div class="UnitHeader-titleHeading">Heading</div><div>package</div>
+</html>`,
+				// "/broken" responds with a 500
+			},
+			expectResults: []SearchResult{
+				{Package: "bar", Version: "v0.0.0-20000204000000-abcdef123456", SemVer: &SemVer{Prerelease: "20000204000000-abcdef123456", IsPseudo: true, PseudoTime: date(2000, 2, 4), PseudoRevision: "abcdef123456"}, Published: "2000-02-04", PublishedTime: date(2000, 2, 4), ImportedBy: 5, License: "MIT", Synopsis: "Package bar does things."},
+				{Package: "broken", Version: "v0.0.0-...-123456", Published: "2000-02-05", PublishedTime: date(2000, 2, 5), ImportedBy: 6, License: "MIT", Synopsis: "Package broken does things."},
+			},
+			expectWarnings: []string{"resolving version of 'broken'"},
+		},
+		{
+			name: "returns an error if the imported by count can't be parsed",
+			req:  SearchRequest{Query: "foo", Limit: 10},
+			pages: map[string]string{
+				"1": `<html><div data-test-id="results-total">1 result</div>` +
+					searchSnippet("foo", "v1.0.0", "Feb 3, 2000", "many") + `</html>`,
+			},
+			expectErrContains: `parsing "many"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/search" {
+					mode := string(c.req.Mode)
+					if mode == "" {
+						mode = "package"
+					}
+					assert.Equal(t, c.req.Query, r.URL.Query().Get("q"))
+					assert.Equal(t, mode, r.URL.Query().Get("m"))
+					rw.Write([]byte(c.pages[r.URL.Query().Get("page")]))
+					return
+				}
+				if r.URL.Path == "/broken" {
+					rw.WriteHeader(500)
+					return
+				}
+				html, ok := c.packages[r.URL.Path]
+				if !ok {
+					rw.WriteHeader(404)
+					return
+				}
+				rw.Write([]byte(html))
+			}, func(addr string) {
+				var mut sync.Mutex
+				var warnings []string
+				client := New(WithBaseURL("http://"+addr), WithWarningHandler(func(warning error) {
+					mut.Lock()
+					defer mut.Unlock()
+					warnings = append(warnings, warning.Error())
+				}))
+				results, err := client.Search(c.req)
+				if c.expectErrContains != "" {
+					assert.Contains(t, err.Error(), c.expectErrContains)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, c.expectResults, results.Results)
+				assert.Equal(t, c.expectSymbolResults, results.SymbolResults)
+				assert.Len(t, warnings, len(c.expectWarnings))
+				for i := range c.expectWarnings {
+					if i < len(warnings) {
+						assert.Contains(t, warnings[i], c.expectWarnings[i])
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestClient_SearchIterator(t *testing.T) {
+	pages := map[string]string{
+		"1": `<html><div data-test-id="results-total">1 - 2 of 3 results</div>` +
+			searchSnippet("foo", "v1.0.0", "Feb 3, 2000", "1") +
+			searchSnippet("bar", "v1.0.0", "Feb 3, 2000", "2") + `</html>`,
+		"2": `<html><div data-test-id="results-total">3 - 3 of 3 results</div>` +
+			searchSnippet("baz", "v1.0.0", "Feb 3, 2000", "3") + `</html>`,
+	}
+	var reqs int32
+	withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&reqs, 1)
+		rw.Write([]byte(pages[r.URL.Query().Get("page")]))
+	}, func(addr string) {
+		client := New(WithBaseURL("http://" + addr))
+
+		t.Run("fetches pages as they're needed", func(t *testing.T) {
+			atomic.StoreInt32(&reqs, 0)
+			it := client.SearchIterator(context.Background(), SearchRequest{Query: "foo"})
+
+			assert.True(t, it.Next())
+			assert.Equal(t, "foo", it.Result().Package)
+			assert.Equal(t, 3, it.Total())
+			assert.True(t, it.Next())
+			assert.Equal(t, "bar", it.Result().Package)
+			assert.EqualValues(t, 1, atomic.LoadInt32(&reqs))
+
+			assert.True(t, it.Next())
+			assert.Equal(t, "baz", it.Result().Package)
+			assert.EqualValues(t, 2, atomic.LoadInt32(&reqs))
+
+			assert.False(t, it.Next())
+			assert.NoError(t, it.Err())
+		})
+
+		t.Run("stops early at the limit", func(t *testing.T) {
+			atomic.StoreInt32(&reqs, 0)
+			it := client.SearchIterator(context.Background(), SearchRequest{Query: "foo", Limit: 2})
+			var pkgs []string
+			for it.Next() {
+				pkgs = append(pkgs, it.Result().Package)
+			}
+			assert.NoError(t, it.Err())
+			assert.Equal(t, []string{"foo", "bar"}, pkgs)
+			assert.EqualValues(t, 1, atomic.LoadInt32(&reqs))
+		})
+	})
+}
+
+func TestClient_SearchNoResults(t *testing.T) {
+	var reqs int32
+	withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&reqs, 1)
+		rw.Write([]byte(`<html><div data-test-id="results-total">0 results</div></html>`))
+	}, func(addr string) {
+		client := New(WithBaseURL("http://" + addr))
+		results, err := client.Search(SearchRequest{Query: "foo", Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, &SearchResults{}, results)
+		assert.EqualValues(t, 1, atomic.LoadInt32(&reqs))
+	})
+}
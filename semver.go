@@ -0,0 +1,102 @@
+package pkggodevclient
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// SemVer is a parsed semantic version, as used by Go modules.
+type SemVer struct {
+	Major int
+	Minor int
+	Patch int
+	// Prerelease is the prerelease part of the version without the leading '-', such as "rc.1"
+	Prerelease string
+	// Build is the build metadata of the version without the leading '+', such as "incompatible"
+	Build string
+	// IsPseudo is true if the version is a pseudo-version, which refers to a commit instead of a tag
+	IsPseudo bool
+	// PseudoTime is the commit time of a pseudo-version
+	PseudoTime time.Time
+	// PseudoRevision is the commit hash prefix of a pseudo-version
+	PseudoRevision string
+}
+
+// ParseSemVer parses a version such as "v1.2.3", "v2.0.0-rc.1+incompatible" or "v0.0.0-20211012000000-abcdefabcdef".
+// Unlike golang.org/x/mod/semver, it doesn't accept shorthands such as "v1.2".
+func ParseSemVer(v string) (SemVer, error) {
+	canonical := semver.Canonical(v)
+	if canonical == "" || canonical+semver.Build(v) != v {
+		return SemVer{}, fmt.Errorf("parsing version '%s': not a valid semantic version", v)
+	}
+	sv := SemVer{
+		Prerelease: strings.TrimPrefix(semver.Prerelease(v), "-"),
+		Build:      strings.TrimPrefix(semver.Build(v), "+"),
+	}
+	core := strings.Split(strings.TrimSuffix(canonical, semver.Prerelease(v))[1:], ".")
+	var err error
+	for i, dst := range []*int{&sv.Major, &sv.Minor, &sv.Patch} {
+		*dst, err = strconv.Atoi(core[i])
+		if err != nil {
+			return SemVer{}, fmt.Errorf("parsing version '%s': %w", v, err)
+		}
+	}
+
+	if module.IsPseudoVersion(v) {
+		t, err := module.PseudoVersionTime(v)
+		if err != nil {
+			// it looks like a pseudo-version, but it's a valid prerelease version, so don't treat it as one
+			return sv, nil
+		}
+		sv.IsPseudo = true
+		sv.PseudoTime = t
+		sv.PseudoRevision, _ = module.PseudoVersionRev(v)
+	}
+	return sv, nil
+}
+
+// parseSemVer parses v, returning nil if it is not a valid semantic version,
+// such as the versions of the standard library or truncated pseudo-versions.
+func parseSemVer(v string) *SemVer {
+	sv, err := ParseSemVer(v)
+	if err != nil {
+		return nil
+	}
+	return &sv
+}
+
+func (v SemVer) String() string {
+	s := fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 if v is lower than, equal to or higher than other, according to semantic versioning.
+// Build metadata is ignored.
+func (v SemVer) Compare(other SemVer) int {
+	return semver.Compare(v.String(), other.String())
+}
+
+func (v SemVer) Less(other SemVer) bool {
+	return v.Compare(other) < 0
+}
+
+// IsPrerelease is true if the version is a prerelease, which includes most pseudo-versions.
+func (v SemVer) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// IsStable is true if the version is v1 or higher and not a prerelease.
+func (v SemVer) IsStable() bool {
+	return v.Major >= 1 && !v.IsPrerelease()
+}
@@ -0,0 +1,109 @@
+package pkggodevclient
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSemVer(t *testing.T) {
+	cases := []struct {
+		version           string
+		expectErrContains string
+		expectSemVer      SemVer
+	}{
+		{version: "v1.2.3", expectSemVer: SemVer{Major: 1, Minor: 2, Patch: 3}},
+		{version: "v2.0.0-rc.1+incompatible", expectSemVer: SemVer{Major: 2, Prerelease: "rc.1", Build: "incompatible"}},
+		{
+			version: "v0.0.0-20211012093045-abcdef123456",
+			expectSemVer: SemVer{
+				Prerelease:     "20211012093045-abcdef123456",
+				IsPseudo:       true,
+				PseudoTime:     time.Date(2021, 10, 12, 9, 30, 45, 0, time.UTC),
+				PseudoRevision: "abcdef123456",
+			},
+		},
+		{
+			version: "v1.2.4-0.20211012093045-abcdef123456",
+			expectSemVer: SemVer{
+				Major: 1, Minor: 2, Patch: 4,
+				Prerelease:     "0.20211012093045-abcdef123456",
+				IsPseudo:       true,
+				PseudoTime:     time.Date(2021, 10, 12, 9, 30, 45, 0, time.UTC),
+				PseudoRevision: "abcdef123456",
+			},
+		},
+		{
+			version: "v1.2.3-pre.0.20211012093045-abcdef123456",
+			expectSemVer: SemVer{
+				Major: 1, Minor: 2, Patch: 3,
+				Prerelease:     "pre.0.20211012093045-abcdef123456",
+				IsPseudo:       true,
+				PseudoTime:     time.Date(2021, 10, 12, 9, 30, 45, 0, time.UTC),
+				PseudoRevision: "abcdef123456",
+			},
+		},
+		{
+			// pseudo-versions without a base version must be vX.0.0
+			version:      "v1.2.3-20211012093045-abcdef123456",
+			expectSemVer: SemVer{Major: 1, Minor: 2, Patch: 3, Prerelease: "20211012093045-abcdef123456"},
+		},
+		{version: "1.2.3", expectErrContains: "parsing version '1.2.3': not a valid semantic version"},
+		{version: "v1.2", expectErrContains: "not a valid semantic version"},
+		{version: "v01.2.3", expectErrContains: "not a valid semantic version"},
+		{version: "v0.0.0-...-abcdef", expectErrContains: "not a valid semantic version"},
+		{version: "go1.17.2", expectErrContains: "not a valid semantic version"},
+		{version: "v99999999999999999999.0.0", expectErrContains: "value out of range"},
+	}
+	for _, c := range cases {
+		t.Run(c.version, func(t *testing.T) {
+			v, err := ParseSemVer(c.version)
+			if c.expectErrContains != "" {
+				assert.Contains(t, err.Error(), c.expectErrContains)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expectSemVer, v)
+			assert.Equal(t, c.version, v.String())
+		})
+	}
+}
+
+func TestSemVer_Compare(t *testing.T) {
+	// sorted from lowest to highest, per the examples in the semver spec
+	sorted := []string{
+		"v0.0.0-20211012093045-abcdef123456",
+		"v0.9.0",
+		"v1.0.0-0.3.7",
+		"v1.0.0-alpha",
+		"v1.0.0-alpha.1",
+		"v1.0.0-alpha.beta",
+		"v1.0.0-beta",
+		"v1.0.0-beta.2",
+		"v1.0.0-beta.11",
+		"v1.0.0-rc.1",
+		"v1.0.0",
+		"v1.0.1-0.20211012093045-abcdef123456",
+		"v1.0.1",
+		"v1.10.0",
+		"v2.0.0+incompatible",
+	}
+	var versions []SemVer
+	for i := len(sorted) - 1; i >= 0; i-- {
+		v, err := ParseSemVer(sorted[i])
+		assert.NoError(t, err)
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Less(versions[j]) })
+	var actual []string
+	for _, v := range versions {
+		actual = append(actual, v.String())
+	}
+	assert.Equal(t, sorted, actual)
+
+	a, _ := ParseSemVer("v1.0.0")
+	b, _ := ParseSemVer("v1.0.0+build")
+	assert.Equal(t, 0, a.Compare(b))
+}
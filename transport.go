@@ -0,0 +1,237 @@
+package pkggodevclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RetryPolicy controls how requests that fail with a 429 or 5xx response are retried.
+// Backoff is exponential with jitter, unless the response has a Retry-After header, which is honored instead.
+// The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts per request, including the first one.
+	MaxAttempts int
+	// MaxElapsed is the maximum time to spend on a request, including backoff.
+	// Zero means there is no limit.
+	MaxElapsed time.Duration
+	// InitialBackoff is the backoff before the first retry, which doubles with each retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the backoff between attempts.
+	// Zero means there is no cap.
+	MaxBackoff time.Duration
+	// OnRetry, if set, is called before waiting to retry a request.
+	OnRetry func(RetryEvent)
+}
+
+// RetryEvent describes a failed attempt that is about to be retried.
+type RetryEvent struct {
+	URL string
+	// Attempt is the number of the attempt that failed, starting at 1.
+	Attempt    int
+	StatusCode int
+	// Wait is how long the client will wait before the next attempt.
+	Wait time.Duration
+}
+
+// DefaultRetryPolicy returns a retry policy suitable for most uses of pkg.go.dev.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		MaxElapsed:     time.Minute,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+	}
+}
+
+func isRetryable(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// backoff returns how long to wait after the given failed attempt, with "equal jitter".
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt && (p.MaxBackoff == 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff != 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a number of seconds or an HTTP date.
+func parseRetryAfter(s string, now time.Time) (time.Duration, bool) {
+	if s == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(s); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(s)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// transport applies the client's request policies to every request a collector makes.
+// colly has no notion of contexts, so this is also how a call's context reaches its requests.
+type transport struct {
+	ctx     context.Context
+	base    http.RoundTripper
+	limiter *rate.Limiter
+	sem     chan struct{}
+	retry   RetryPolicy
+	cache   Cache
+	// cacheTTL is the TTL for the endpoint being requested, where zero disables caching
+	cacheTTL time.Duration
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.WithContext(t.ctx)
+	if t.cache == nil || t.cacheTTL <= 0 || req.Method != http.MethodGet {
+		return t.roundTripWithRetries(req)
+	}
+
+	key := req.URL.String()
+	if !shouldRefresh(t.ctx) {
+		if b, ok := t.cache.Get(key); ok {
+			if resp, ok := decodeCachedResponse(req, b); ok {
+				return resp, nil
+			}
+		}
+	}
+	resp, err := t.roundTripWithRetries(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	t.cache.Set(key, encodeCachedResponse(resp.Header.Get("Content-Type"), body), t.cacheTTL)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// cached responses are stored as the content type, a newline, and then the body
+func encodeCachedResponse(contentType string, body []byte) []byte {
+	return append([]byte(contentType+"\n"), body...)
+}
+
+func decodeCachedResponse(req *http.Request, b []byte) (*http.Response, bool) {
+	i := bytes.IndexByte(b, '\n')
+	if i == -1 {
+		return nil, false
+	}
+	header := http.Header{}
+	if contentType := string(b[:i]); contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	body := b[i+1:]
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, true
+}
+
+func (t *transport) roundTripWithRetries(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		resp, err := t.roundTripOnce(req)
+		if err != nil || attempt >= t.retry.MaxAttempts || !isRetryable(resp.StatusCode) {
+			return resp, err
+		}
+
+		wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if !ok {
+			wait = t.retry.backoff(attempt)
+		}
+		if t.retry.MaxElapsed != 0 && time.Since(start)+wait > t.retry.MaxElapsed {
+			return resp, nil
+		}
+		// drain the body so the connection can be reused
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+		resp.Body.Close()
+
+		if t.retry.OnRetry != nil {
+			t.retry.OnRetry(RetryEvent{
+				URL:        req.URL.String(),
+				Attempt:    attempt,
+				StatusCode: resp.StatusCode,
+				Wait:       wait,
+			})
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-t.ctx.Done():
+			timer.Stop()
+			return nil, t.ctx.Err()
+		}
+	}
+}
+
+func (t *transport) roundTripOnce(req *http.Request) (*http.Response, error) {
+	release := func() {}
+	if t.sem != nil {
+		select {
+		case t.sem <- struct{}{}:
+		case <-t.ctx.Done():
+			return nil, t.ctx.Err()
+		}
+		once := &sync.Once{}
+		release = func() { once.Do(func() { <-t.sem }) }
+	}
+	if t.limiter != nil {
+		if err := t.limiter.Wait(t.ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	// the request is in flight until its body is consumed
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
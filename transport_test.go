@@ -0,0 +1,193 @@
+package pkggodevclient
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_MaxParallelism(t *testing.T) {
+	var inFlight, maxInFlight int32
+	withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}, func(addr string) {
+		client := New(WithBaseURL("http://"+addr), WithMaxParallelism(2))
+		wg := &sync.WaitGroup{}
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := client.ImportedBy(ImportedByRequest{Package: "somepackage"})
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+	})
+	assert.EqualValues(t, 2, maxInFlight)
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {}, func(addr string) {
+		client := New(WithBaseURL("http://"+addr), WithRateLimit(20, 1))
+		start := time.Now()
+		for i := 0; i < 5; i++ {
+			_, err := client.Licenses(LicensesRequest{Package: "somepackage"})
+			assert.NoError(t, err)
+		}
+		// the first request is free, the following four wait 50ms each
+		assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	})
+}
+
+func TestClient_UserAgent(t *testing.T) {
+	cases := []struct {
+		name            string
+		options         []Option
+		expectUserAgent string
+	}{
+		{
+			name:            "default user agent",
+			expectUserAgent: "pkggodev-client (https://github.com/guseggert/pkggodev-client)",
+		},
+		{
+			name:            "custom user agent",
+			options:         []Option{WithUserAgent("foo/1.0")},
+			expectUserAgent: "foo/1.0",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var userAgent string
+			withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+				userAgent = r.UserAgent()
+			}, func(addr string) {
+				client := New(append(c.options, WithBaseURL("http://"+addr))...)
+				_, err := client.Imports(ImportsRequest{Package: "somepackage"})
+				assert.NoError(t, err)
+			})
+			assert.Equal(t, c.expectUserAgent, userAgent)
+		})
+	}
+}
+
+func TestClient_RobotsTxt(t *testing.T) {
+	withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			rw.Write([]byte("User-agent: *\nDisallow: /somepackage\n"))
+			return
+		}
+		rw.Write([]byte(`<html><div class="u-breakWord">foo</div></html>`))
+	}, func(addr string) {
+		_, err := New(WithBaseURL("http://" + addr)).ImportedBy(ImportedByRequest{Package: "somepackage"})
+		assert.NoError(t, err)
+
+		_, err = New(WithBaseURL("http://"+addr), WithRobotsTxt()).ImportedBy(ImportedByRequest{Package: "somepackage"})
+		assert.ErrorIs(t, err, colly.ErrRobotsTxtBlocked)
+	})
+}
+
+func TestClient_InvalidURL(t *testing.T) {
+	_, err := New(WithBaseURL("http://[::1")).Versions(VersionsRequest{Package: "somepackage"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "visiting http://[::1/somepackage?tab=versions")
+
+	_, err = New(WithBaseURL("http://127.0.0.1:0")).DescribePackage(DescribePackageRequest{Package: "foo%zz"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid URL escape")
+}
+
+func TestClient_Retry(t *testing.T) {
+	cases := []struct {
+		name              string
+		statusCodes       []int
+		expectErrContains string
+		expectRetries     []int
+	}{
+		{
+			name:          "recovers from transient errors",
+			statusCodes:   []int{503, 500, 200},
+			expectRetries: []int{503, 500},
+		},
+		{
+			name:          "retries when rate limited",
+			statusCodes:   []int{429, 200},
+			expectRetries: []int{429},
+		},
+		{
+			name:              "gives up after max attempts",
+			statusCodes:       []int{503, 503, 503, 503},
+			expectErrContains: "Service Unavailable",
+			expectRetries:     []int{503, 503},
+		},
+		{
+			name:              "does not retry 404s",
+			statusCodes:       []int{404},
+			expectErrContains: "not found on pkg.go.dev",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var reqs int32
+			withHTTPServer("/", func(rw http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&reqs, 1)
+				rw.Header().Set("Retry-After", "0")
+				rw.WriteHeader(c.statusCodes[n-1])
+			}, func(addr string) {
+				var retries []int
+				policy := RetryPolicy{
+					MaxAttempts:    3,
+					InitialBackoff: time.Millisecond,
+					OnRetry: func(e RetryEvent) {
+						assert.Equal(t, len(retries)+1, e.Attempt)
+						assert.Equal(t, "http://"+addr+"/somepackage?tab=importedby", e.URL)
+						retries = append(retries, e.StatusCode)
+					},
+				}
+				client := New(WithBaseURL("http://"+addr), WithRetryPolicy(policy))
+				_, err := client.ImportedBy(ImportedByRequest{Package: "somepackage"})
+				if c.expectErrContains != "" {
+					assert.Contains(t, err.Error(), c.expectErrContains)
+				} else {
+					assert.NoError(t, err)
+				}
+				assert.Equal(t, c.expectRetries, retries)
+			})
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		value      string
+		expectWait time.Duration
+		expectOK   bool
+	}{
+		{value: "", expectOK: false},
+		{value: "120", expectWait: 2 * time.Minute, expectOK: true},
+		{value: "-1", expectOK: false},
+		{value: "Fri, 01 Oct 2021 12:00:30 GMT", expectWait: 30 * time.Second, expectOK: true},
+		{value: "Fri, 01 Oct 2021 11:00:00 GMT", expectWait: 0, expectOK: true},
+		{value: "soon", expectOK: false},
+	}
+	for _, c := range cases {
+		t.Run(c.value, func(t *testing.T) {
+			wait, ok := parseRetryAfter(c.value, now)
+			assert.Equal(t, c.expectOK, ok)
+			assert.Equal(t, c.expectWait, wait)
+		})
+	}
+}